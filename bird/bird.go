@@ -2,23 +2,51 @@ package bird
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"os/exec"
+
+	"github.com/alice-lg/birdwatcher/metrics"
 )
 
 type Cache interface {
 	Set(key string, val Parsed, ttl int) error
 	Get(key string) (Parsed, error)
+	GetStale(key string) (Parsed, error)
 	Expire() int
 }
 
+var (
+	cacheRequests = metrics.NewCounter(
+		"birdwatcher_cache_requests_total",
+		"Lookups in the cache by result (hit or miss)")
+	birdcRuns = metrics.NewCounter(
+		"birdwatcher_birdc_runs_total",
+		"Commands run with birdc")
+	birdcSeconds = metrics.NewCounter(
+		"birdwatcher_birdc_seconds_total",
+		"Time spent running commands with birdc")
+	parseErrors = metrics.NewCounter(
+		"birdwatcher_parse_errors_total",
+		"Lines of the birdc output which could not be parsed, by endpoint")
+)
+
 var ClientConf BirdConfig
 var StatusConf StatusConfig
 var IPVersion = "4"
@@ -33,52 +61,293 @@ var RunQueue sync.Map // queue birdc commands before execution
 
 var NilParse Parsed = (Parsed)(nil) // special Parsed values
 var BirdError Parsed = Parsed{"error": "bird unreachable"}
+var BirdNotReady Parsed = Parsed{"error": "bird not ready"}
+var BirdOutputTooLarge Parsed = Parsed{"error": "birdc output too large"}
 
 func IsSpecial(ret Parsed) bool { // test for special Parsed values
-	return reflect.DeepEqual(ret, NilParse) || reflect.DeepEqual(ret, BirdError)
+	return reflect.DeepEqual(ret, NilParse) ||
+		reflect.DeepEqual(ret, BirdError) ||
+		reflect.DeepEqual(ret, BirdNotReady) ||
+		reflect.DeepEqual(ret, BirdOutputTooLarge) ||
+		isNotFound(ret)
+}
+
+// Error results carry the HTTP status code the request
+// should be answered with in-band, like the cache does
+// with the ttl.
+const ErrorStatusKey = "error_status"
+
+// NotFound creates the result for a command, which BIRD
+// rejected because the named object does not exist.
+func NotFound(message string) Parsed {
+	return Parsed{"error": message, ErrorStatusKey: http.StatusNotFound}
+}
+
+func isNotFound(ret Parsed) bool {
+	status, ok := ret[ErrorStatusKey].(int)
+	return ok && status == http.StatusNotFound
+}
+
+// ErrBirdNotReady is returned by Run, if BIRD does not
+// answer the command because it is reconfiguring or
+// shutting down. The output must not be parsed or cached.
+var ErrBirdNotReady = errors.New("bird is not ready")
+
+// Replies of BIRD while it can not process commands
+var notReadyReplies = []string{
+	"Reconfiguration in progress",
+	"Reconfiguration already in progress",
+	"Shutdown in progress",
+}
+
+// Check if the output of birdc is a not ready reply
+// instead of the result of the command.
+func isNotReady(out []byte) bool {
+	lines := newLineIterator(bytes.NewReader(out), true)
+	for lines.next() {
+		line := lines.string()
+		if specialLine(line) {
+			continue
+		}
+		for _, reply := range notReadyReplies {
+			if strings.HasPrefix(line, reply) {
+				return true
+			}
+		}
+		return false // Only the first reply is relevant
+	}
+	return false
+}
+
+// NotFoundError is returned by Run, if BIRD rejected the
+// command because the protocol, table or other object
+// named in it does not exist.
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+// Replies of BIRD to commands naming unknown objects
+var notFoundReplies = []*regexp.Regexp{
+	regexp.MustCompile(`^No such `),
+	regexp.MustCompile(`^No protocols match`),
+	regexp.MustCompile(`^\S+ is not a (protocol|table)$`),
+	regexp.MustCompile(`^syntax error, unexpected CF_SYM_UNDEFINED`),
+}
+
+// Get the not found reply of BIRD from the output
+// of birdc, if the command was rejected.
+func notFoundReply(out []byte) (string, bool) {
+	lines := newLineIterator(bytes.NewReader(out), true)
+	for lines.next() {
+		line := strings.TrimSpace(lines.string())
+		if specialLine(line) {
+			continue
+		}
+		for _, reply := range notFoundReplies {
+			if reply.MatchString(line) {
+				return line, true
+			}
+		}
+		return "", false // Only the first reply is relevant
+	}
+	return "", false
 }
 
 // intitialize the Cache once during setup with either a MemoryCache or
 // RedisCache implementation.
 // TODO implement singleton pattern
 func InitializeCache() {
-	var err error
-	if CacheConf.UseRedis {
-		cache, err = NewRedisCache(CacheConf)
-		if err != nil {
-			log.Println("Could not initialize redis cache, falling back to memory cache:", err)
+	if !CacheConf.UseRedis {
+		cache = newConfiguredMemoryCache()
+		return
+	}
+
+	redisCache, err := connectRedisCache()
+	if err == nil {
+		cache = withMemoryTier(redisCache)
+		return
+	}
+	if !CacheConf.RedisFallback {
+		log.Fatal("Could not initialize redis cache: ", err)
+	}
+	log.Println("WARNING: Could not initialize redis cache, falling back to memory cache:", err)
+	cache = newConfiguredMemoryCache()
+}
+
+// Get the TTL of the memory tier from
+// the config, defaults to 5 seconds.
+func memoryTierTtl() time.Duration {
+	if CacheConf.MemoryTierTtl > 0 {
+		return time.Duration(CacheConf.MemoryTierTtl) * time.Second
+	}
+	return 5 * time.Second
+}
+
+// Put a memory tier in front of the shared
+// cache, if configured.
+func withMemoryTier(shared Cache) Cache {
+	if CacheConf.MemoryTierKeys <= 0 {
+		return shared
+	}
+	log.Println("Initialized memory tier with maxKeys:", CacheConf.MemoryTierKeys,
+		"and TTL:", memoryTierTtl())
+	return NewTieredCache(shared, CacheConf.MemoryTierKeys, memoryTierTtl())
+}
+
+func newConfiguredMemoryCache() *MemoryCache {
+	maxKeys := CacheConf.MaxKeys
+	maxKeysDefault := 60
+	if maxKeys == 0 {
+		log.Println("MaxKeys not set, using default value:", maxKeysDefault)
+		maxKeys = maxKeysDefault
+	}
+
+	memoryCache := NewMemoryCache(maxKeys)
+	memoryCache.staleTime = staleTime()
+	memoryCache.jitter = ttlJitter()
+	log.Println("Initialized MemoryCache with maxKeys:", maxKeys)
+	return memoryCache
+}
+
+// Maximum wait between connection attempts to redis
+const redisMaxRetryInterval = 30 * time.Second
+
+// Get the wait before retrying to connect to redis. The
+// interval is doubled with every failed attempt.
+func redisRetryInterval(attempt int) time.Duration {
+	interval := time.Second
+	if CacheConf.RedisRetryInterval > 0 {
+		interval = time.Duration(CacheConf.RedisRetryInterval) * time.Second
+	}
+	for i := 0; i < attempt && interval < redisMaxRetryInterval; i++ {
+		interval *= 2
+	}
+	if interval > redisMaxRetryInterval {
+		return redisMaxRetryInterval
+	}
+	return interval
+}
+
+// Connect to redis, which might not be available yet
+// on startup. Failed attempts are retried as configured.
+func connectRedisCache() (*RedisCache, error) {
+	for attempt := 0; ; attempt++ {
+		redisCache, err := NewRedisCache(CacheConf)
+		if err == nil {
+			return redisCache, nil
 		}
-	} else { // initialize the MemoryCache
-		maxKeys := CacheConf.MaxKeys
-		maxKeysDefault := 60
-		if maxKeys == 0 {
-			log.Println("MaxKeys not set, using default value:", maxKeysDefault)
-			maxKeys = maxKeysDefault
+		if attempt >= CacheConf.RedisRetries {
+			return nil, err
 		}
 
-		cache = NewMemoryCache(maxKeys)
-		log.Println("Initialized MemoryCache with maxKeys:", maxKeys)
+		interval := redisRetryInterval(attempt)
+		log.Println("Could not connect to redis, retrying in", interval, "-", err)
+		time.Sleep(interval)
 	}
 }
 
+// SaveCache writes a snapshot of the MemoryCache to the
+// configured file. Nothing is saved when using redis.
+func SaveCache() error {
+	memoryCache, ok := cache.(*MemoryCache)
+	if !ok || CacheConf.PersistFile == "" {
+		return nil
+	}
+	return memoryCache.Save(CacheConf.PersistFile)
+}
+
+// LoadCache restores the MemoryCache from the snapshot
+// in the configured file, if present.
+func LoadCache() {
+	memoryCache, ok := cache.(*MemoryCache)
+	if !ok || CacheConf.PersistFile == "" {
+		return
+	}
+
+	count, err := memoryCache.Load(CacheConf.PersistFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Println("Could not load cache snapshot:", err)
+		}
+		return
+	}
+	log.Println("Restored", count, "entries from cache snapshot:", CacheConf.PersistFile)
+}
+
 // ExpireCache is a convenience method to expire the cache.
 func ExpireCache() int {
 	return cache.Expire()
 }
 
-/* Convenience method to make new entries in the cache.
- * Abstracts over the specific caching implementation and the ability to set
- * individual TTL values for entries. Always use the default TTL value from the
- * config.
- */
-func toCache(key string, val Parsed) bool {
-	var ttl int
+// The default TTL value for cache entries from the config
+func defaultCacheTtl() int {
 	if ClientConf.CacheTtl >= 0 {
-		ttl = ClientConf.CacheTtl
-	} else {
-		ttl = 5 // five minutes
+		return ClientConf.CacheTtl
 	}
+	return 5 // five minutes
+}
 
+// Expired cache entries are retained for this time
+// to be served if BIRD fails.
+func staleTime() time.Duration {
+	if CacheConf.StaleWhileError > 0 {
+		return time.Duration(CacheConf.StaleWhileError) * time.Minute
+	}
+	return 0
+}
+
+// Fraction of the TTL by which the expiry of cache
+// entries is randomly spread out, at most 50%.
+func ttlJitter() float64 {
+	switch {
+	case CacheConf.TtlJitter <= 0:
+		return 0
+	case CacheConf.TtlJitter > 0.5:
+		return 0.5
+	}
+	return CacheConf.TtlJitter
+}
+
+// Randomly shift the TTL by up to the jitter fraction in
+// either direction, so entries cached at the same time
+// do not expire all at once.
+func jitterTtl(ttl time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration((rand.Float64()*2-1)*jitter*float64(ttl))
+}
+
+// The TTL of the cached results of a module, configured
+// with the ttl table of the [cache] section. Falls back to
+// the given TTL if not configured.
+func moduleCacheTtl(module string, ttl int) int {
+	if t := CacheConf.Ttl[module]; module != "" && t > 0 {
+		return t
+	}
+	return ttl
+}
+
+// Results without any routes, e.g. the lookup of a net
+// which is not routed, are cached with the TTL of this key
+// in the ttl table, if configured.
+const negativeTtlKey = "negative"
+
+// Check if the result of a route query has no routes
+func isNegativeResult(parsed Parsed) bool {
+	routes, ok := parsed["routes"].([]Parsed)
+	return ok && len(routes) == 0
+}
+
+/* Convenience method to make new entries in the cache.
+ * Abstracts over the specific caching implementation and the ability to set
+ * individual TTL values for entries.
+ */
+func toCache(key string, val Parsed, ttl int) bool {
 	if err := cache.Set(key, val, ttl); err != nil {
 		log.Println(err)
 		return false
@@ -97,14 +366,44 @@ func toCache(key string, val Parsed) bool {
 func fromCache(key string) (Parsed, bool) {
 	val, err := cache.Get(key)
 	if err == nil {
+		cacheRequests.Inc("result", "hit")
 		return val, true
 	} else {
+		cacheRequests.Inc("result", "miss")
 		return val, false
 	}
 	//DEBUG log.Println(err)
 
 }
 
+// TimingKey holds the time running birdc and parsing
+// the output took, if timing is enabled for debugging.
+const TimingKey = "_timing"
+
+// StaleKey marks a result which is served from the
+// cache after its TTL expired, because BIRD failed.
+const StaleKey = "_stale"
+
+// Get an expired entry from the cache, if stale entries
+// are retained. The cached entry is not modified.
+func fromCacheStale(key string) (Parsed, bool) {
+	if staleTime() == 0 {
+		return NilParse, false
+	}
+
+	val, err := cache.GetStale(key)
+	if err != nil {
+		return NilParse, false
+	}
+
+	stale := Parsed{}
+	for k, v := range val {
+		stale[k] = v
+	}
+	stale[StaleKey] = true
+	return stale, true
+}
+
 // Determines the key in the cache, where the result of specific functions are stored.
 // Eliminates the need to know what command was executed by that function.
 func GetCacheKey(fname string, fargs ...interface{}) string {
@@ -120,7 +419,133 @@ func GetCacheKey(fname string, fargs ...interface{}) string {
 	return key
 }
 
+// ErrOutputTooLarge is returned by Run, if the output
+// of birdc exceeds the configured maximum size.
+var ErrOutputTooLarge = errors.New("birdc output exceeds the maximum size")
+
+// ErrCommandTimeout is returned by Run, if birdc
+// does not finish within the timeout of the command.
+var ErrCommandTimeout = errors.New("birdc command timed out")
+
+// RunOptions of a query. The cached result is used if
+// UseCache is set. Exempt queries are not rate limited,
+// e.g. the queries of trusted clients.
+type RunOptions struct {
+	UseCache bool
+	Exempt   bool
+
+	// The module of the query, selecting the TTL
+	// of the cached result
+	Module string
+
+	// Include the raw output of birdc in the result,
+	// if it is kept by the parser
+	Raw bool
+}
+
+// RunLimits restrict a single birdc command more than the
+// global settings, e.g. for commands given by clients.
+// Unset limits fall back to the global settings.
+type RunLimits struct {
+	Timeout        time.Duration
+	MaxOutputBytes int64
+}
+
+// Get the maximum output size of the limits
+func (l RunLimits) maxOutputBytes() int64 {
+	if l.MaxOutputBytes > 0 {
+		return l.MaxOutputBytes
+	}
+	return maxOutputBytes()
+}
+
+// The maximum size of the birdc output from the config
+func maxOutputBytes() int64 {
+	if ClientConf.MaxOutputBytes > 0 {
+		return ClientConf.MaxOutputBytes
+	}
+	return 1 << 30 // 1 GiB
+}
+
+// Run the command and read at most max bytes of its output.
+// The command is killed if the output exceeds the limit.
+func runLimited(cmd *exec.Cmd, max int64) ([]byte, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	out, err := ioutil.ReadAll(io.LimitReader(stdout, max+1))
+	if err == nil && int64(len(out)) > max {
+		err = ErrOutputTooLarge
+	}
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Render a command line, which can be pasted into a shell
+// to run the command again. Arguments with other than safe
+// characters, like quoted table names, are single-quoted.
+func shellCommand(name string, args []string) string {
+	words := []string{}
+	for _, word := range append([]string{name}, args...) {
+		if word != "" && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=,+@") == "" {
+			words = append(words, word)
+			continue
+		}
+		words = append(words, "'"+strings.Replace(word, "'", `'\''`, -1)+"'")
+	}
+	return strings.Join(words, " ")
+}
+
 func Run(args string) (io.Reader, error) {
+	return runWithLimits(args, RunLimits{})
+}
+
+// Like Run, but with the limits applied. The command
+// is killed when it exceeds the timeout.
+// Concurrent birdc runs, limited by max_concurrent_birdc
+var birdcRunning = struct {
+	sync.Mutex
+	cond  *sync.Cond
+	count int
+}{}
+
+func init() {
+	birdcRunning.cond = sync.NewCond(&birdcRunning)
+}
+
+// Wait for a slot to run birdc, if the maximum
+// of concurrent runs is reached.
+func acquireBirdc() {
+	birdcRunning.Lock()
+	defer birdcRunning.Unlock()
+	for ClientConf.MaxConcurrentBirdc > 0 &&
+		birdcRunning.count >= ClientConf.MaxConcurrentBirdc {
+		birdcRunning.cond.Wait()
+	}
+	birdcRunning.count++
+}
+
+func releaseBirdc() {
+	birdcRunning.Lock()
+	defer birdcRunning.Unlock()
+	birdcRunning.count--
+	birdcRunning.cond.Signal()
+}
+
+func runWithLimits(args string, limits RunLimits) (io.Reader, error) {
 	args = "-r " + "show " + args // enforce birdc in restricted mode with "-r" argument
 	argsList := strings.Split(args, " ")
 
@@ -133,10 +558,35 @@ func Run(args string) (io.Reader, error) {
 	cmd = append(cmd, cmdArgs...)
 	cmd = append(cmd, argsList...)
 
-	out, err := exec.Command(birdc, cmd...).Output()
+	ctx := context.Background()
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+
+	if ClientConf.LogCommands {
+		log.Println("Running birdc:", shellCommand(birdc, cmd))
+	}
+
+	acquireBirdc()
+	start := time.Now()
+	out, err := runLimited(exec.CommandContext(ctx, birdc, cmd...), limits.maxOutputBytes())
+	releaseBirdc()
+	birdcRuns.Inc()
+	birdcSeconds.Add(time.Since(start).Seconds())
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, ErrCommandTimeout
+	}
 	if err != nil {
 		return nil, err
 	}
+	if isNotReady(out) {
+		return nil, ErrBirdNotReady
+	}
+	if message, ok := notFoundReply(out); ok {
+		return nil, &NotFoundError{message}
+	}
 
 	return bytes.NewReader(out), nil
 }
@@ -175,20 +625,29 @@ func checkRateLimit() bool {
 	return true
 }
 
-func RunAndParse(useCache bool, key string, cmd string, parser func(io.Reader) Parsed, updateCache func(*Parsed)) (Parsed, bool) {
+func RunAndParse(opts RunOptions, key string, cmd string, parser func(io.Reader) Parsed, updateCache func(*Parsed)) (Parsed, bool) {
+	return runAndParseLimited(RunLimits{}, defaultCacheTtl(), opts, key, cmd, cmd, parser, updateCache)
+}
+
+// Like RunAndParse, but birdc is run with the limits and
+// the result is cached with the given TTL under the cache
+// key, which is also used to queue concurrent runs.
+func runAndParseLimited(limits RunLimits, ttl int, opts RunOptions, key string, cacheKey string, cmd string, parser func(io.Reader) Parsed, updateCache func(*Parsed)) (Parsed, bool) {
 	var wg sync.WaitGroup
 
-	if useCache {
-		if val, ok := fromCache(cmd); ok {
-			return val, true
+	if opts.UseCache {
+		if val, ok := fromCache(cacheKey); ok {
+			return withRawOutput(opts, cacheKey, val), true
 		}
 	}
 
 	wg.Add(1)
-	if queueGroup, queueLoaded := RunQueue.LoadOrStore(cmd, &wg); queueLoaded {
+	if queueGroup, queueLoaded := RunQueue.LoadOrStore(cacheKey, &wg); queueLoaded {
 		(*queueGroup.(*sync.WaitGroup)).Wait()
 
-		if val, ok := fromCache(cmd); ok {
+		if val, ok := fromCache(cacheKey); ok {
+			return withRawOutput(opts, cacheKey, val), true
+		} else if val, ok := fromCacheStale(cacheKey); ok {
 			return val, true
 		} else {
 			// TODO BirdError should also be signaled somehow
@@ -196,35 +655,138 @@ func RunAndParse(useCache bool, key string, cmd string, parser func(io.Reader) P
 		}
 	}
 
-	if !checkRateLimit() {
+	if !opts.Exempt && !checkRateLimit() {
 		wg.Done()
-		RunQueue.Delete(cmd)
+		RunQueue.Delete(cacheKey)
 		return NilParse, false
 	}
 
-	out, err := Run(cmd)
+	execStart := time.Now()
+	out, err := runWithLimits(cmd, limits)
+	execTime := time.Since(execStart)
+	if notFound, ok := err.(*NotFoundError); ok {
+		// The object is gone, a stale result would be wrong
+		wg.Done()
+		RunQueue.Delete(cacheKey)
+		return NotFound(notFound.Message), false
+	}
+	if err != nil {
+		if val, ok := fromCacheStale(cacheKey); ok {
+			log.Println("Serving stale result, birdc failed:", err)
+			wg.Done()
+			RunQueue.Delete(cacheKey)
+			return val, true
+		}
+	}
+	if err == ErrBirdNotReady {
+		wg.Done()
+		RunQueue.Delete(cacheKey)
+		return BirdNotReady, false
+	}
+	if err == ErrCommandTimeout {
+		log.Println("Aborted command:", cmd, err)
+		wg.Done()
+		RunQueue.Delete(cacheKey)
+		return Parsed{"error": err.Error(), ErrorStatusKey: http.StatusGatewayTimeout}, false
+	}
+	if err == ErrOutputTooLarge {
+		log.Println("Aborted command:", cmd, err)
+		wg.Done()
+		RunQueue.Delete(cacheKey)
+		return BirdOutputTooLarge, false
+	}
 	if err != nil {
 		// ignore errors for now
 		wg.Done()
-		RunQueue.Delete(cmd)
+		RunQueue.Delete(cacheKey)
 		return BirdError, false
 	}
 
+	// Keep the raw output for debugging parser gaps
+	var raw []byte
+	if ParserConf.RawOutput {
+		raw, _ = ioutil.ReadAll(out)
+		out = bytes.NewReader(raw)
+	}
+
+	parseStart := time.Now()
 	parsed := parser(out)
+	parseTime := time.Since(parseStart)
+	countParseErrors(key, parsed)
 
 	if updateCache != nil {
 		updateCache(&parsed)
 	}
 
-	toCache(cmd, parsed)
+	ttl = moduleCacheTtl(opts.Module, ttl)
+	if isNegativeResult(parsed) {
+		ttl = moduleCacheTtl(negativeTtlKey, ttl)
+	}
+	toCache(cacheKey, parsed, ttl)
+	if raw != nil {
+		toCache(rawCacheKey(cacheKey), Parsed{"_raw": string(raw)}, ttl)
+	}
 
 	wg.Done()
-	RunQueue.Delete(cmd)
+	RunQueue.Delete(cacheKey)
+
+	if ParserConf.DebugTiming {
+		// The timing is not cached
+		timed := Parsed{}
+		for k, v := range withRawOutput(opts, cacheKey, parsed) {
+			timed[k] = v
+		}
+		timed[TimingKey] = Parsed{
+			"exec_ms":  durationMs(execTime),
+			"parse_ms": durationMs(parseTime),
+		}
+		return timed, false
+	}
+
+	return withRawOutput(opts, cacheKey, parsed), false
+}
 
-	return parsed, false
+// The raw output of a command is cached with its own key,
+// so it is only fetched if requested.
+func rawCacheKey(cmd string) string {
+	return "raw " + cmd
 }
 
-func Status(useCache bool) (Parsed, bool) {
+// Add the cached raw output of the command to the result,
+// if requested. The result is shared with the cache.
+func withRawOutput(opts RunOptions, cmd string, parsed Parsed) Parsed {
+	if !opts.Raw || !ParserConf.RawOutput {
+		return parsed
+	}
+	raw, ok := fromCache(rawCacheKey(cmd))
+	if !ok {
+		return parsed
+	}
+
+	res := Parsed{}
+	for k, v := range parsed {
+		res[k] = v
+	}
+	res["_raw"] = raw["_raw"]
+	return res
+}
+
+// Count the lines which could not be parsed by the endpoint,
+// which is the function name of the cache key.
+func countParseErrors(key string, parsed Parsed) {
+	if failed, ok := parsed[parseErrorCountKey].(int); ok {
+		endpoint := strings.SplitN(key, "_", 2)[0]
+		parseErrors.Add(float64(failed), "endpoint", endpoint)
+	}
+	delete(parsed, parseErrorCountKey)
+}
+
+// Get a duration in milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func Status(opts RunOptions) (Parsed, bool) {
 	updateParsedCache := func(p *Parsed) {
 		status := (*p)["status"].(Parsed)
 
@@ -253,16 +815,49 @@ func Status(useCache bool) (Parsed, bool) {
 		}
 	}
 
-	birdStatus, from_cache := RunAndParse(useCache, GetCacheKey("Status"), "status", parseStatus, updateParsedCache)
+	birdStatus, from_cache := RunAndParse(opts, GetCacheKey("Status"), "status", parseStatus, updateParsedCache)
 	return birdStatus, from_cache
 }
 
-func ProtocolsShort(useCache bool) (Parsed, bool) {
-	res, from_cache := RunAndParse(useCache, GetCacheKey("ProtocolsShort"), "protocols", parseProtocolsShort, nil)
+// ProtocolsShort gets the terse protocols list, which is
+// cached with the TTL of the states as it is polled.
+func ProtocolsShort(opts RunOptions) (Parsed, bool) {
+	res, from_cache := RunAndParse(opts, GetCacheKey("ProtocolsShort"), "protocols", parseProtocolsShort, nil)
 	return res, from_cache
 }
 
-func Protocols(useCache bool) (Parsed, bool) {
+// Derive a result from the terse protocols list. The result
+// is cached with its own key and TTL, so the terse list is
+// refreshed from BIRD when the derived result expires.
+func fromProtocolsShort(ttl int, opts RunOptions, key string, derive func(Parsed) Parsed) (Parsed, bool) {
+	if opts.UseCache {
+		if val, ok := fromCache(key); ok {
+			return val, true
+		}
+	}
+
+	protocols, _ := ProtocolsShort(RunOptions{Exempt: opts.Exempt})
+	if IsSpecial(protocols) {
+		return protocols, false
+	}
+
+	res := derive(protocols)
+	toCache(key, res, moduleCacheTtl(opts.Module, ttl))
+	return res, false
+}
+
+// The protocol states are polled frequently, so
+// they are cached for 1 minute by default.
+func ProtocolsStates(opts RunOptions) (Parsed, bool) {
+	return fromProtocolsShort(1, opts, GetCacheKey("ProtocolsStates"), protocolsStates)
+}
+
+// ProtocolsBgpSummary gets the BGP sessions grouped by state
+func ProtocolsBgpSummary(opts RunOptions) (Parsed, bool) {
+	return fromProtocolsShort(1, opts, GetCacheKey("ProtocolsBgpSummary"), bgpSummary)
+}
+
+func Protocols(opts RunOptions) (Parsed, bool) {
 	createMetaCache := func(p *Parsed) {
 		metaProtocol := Parsed{"protocols": Parsed{"bird_protocol": Parsed{}}}
 
@@ -278,15 +873,15 @@ func Protocols(useCache bool) (Parsed, bool) {
 			metaProtocol["protocols"].(Parsed)["bird_protocol"].(Parsed)[birdProtocol].(Parsed)[protocol] = &parsed
 		}
 
-		toCache(GetCacheKey("metaProtocol"), metaProtocol)
+		toCache(GetCacheKey("metaProtocol"), metaProtocol, defaultCacheTtl())
 	}
 
-	res, from_cache := RunAndParse(useCache, GetCacheKey("Protocols"), "protocols all", parseProtocols, createMetaCache)
+	res, from_cache := RunAndParse(opts, GetCacheKey("Protocols"), "protocols all", parseProtocols, createMetaCache)
 	return res, from_cache
 }
 
-func ProtocolsBgp(useCache bool) (Parsed, bool) {
-	protocols, from_cache := Protocols(useCache)
+func ProtocolsBgp(opts RunOptions) (Parsed, bool) {
+	protocols, from_cache := Protocols(opts)
 	if IsSpecial(protocols) {
 		return protocols, from_cache
 	}
@@ -297,29 +892,141 @@ func ProtocolsBgp(useCache bool) (Parsed, bool) {
 	bgpProtocols := Parsed{}
 
 	for key, protocol := range metaProtocol["bird_protocol"].(Parsed)["BGP"].(Parsed) {
-		bgpProtocols[key] = *(protocol.(*Parsed))
+		switch p := protocol.(type) {
+		case *Parsed:
+			bgpProtocols[key] = *p
+		case Parsed: // restored from a cache snapshot
+			bgpProtocols[key] = p
+		}
 	}
 
-	return Parsed{"protocols": bgpProtocols,
+	res := Parsed{"protocols": bgpProtocols,
 		"ttl":       protocols["ttl"],
-		"cached_at": protocols["cached_at"]}, from_cache
+		"cached_at": protocols["cached_at"]}
+	if raw, ok := protocols["_raw"]; ok {
+		res["_raw"] = raw
+	}
+
+	return res, from_cache
 }
 
-func Symbols(useCache bool) (Parsed, bool) {
-	return RunAndParse(useCache, GetCacheKey("Symbols"), "symbols", parseSymbols, nil)
+// Get the local AS shared by all BGP protocols. If the
+// protocols use different local ASes, nil is returned.
+func protocolsLocalAS(protocols Parsed) interface{} {
+	var localAS interface{}
+
+	res, _ := protocols["protocols"].(Parsed)
+	for _, p := range res {
+		protocol, ok := p.(Parsed)
+		if !ok || protocol["bird_protocol"] != "BGP" {
+			continue
+		}
+		as, ok := protocol["local_as"]
+		if !ok {
+			continue
+		}
+		if localAS != nil && localAS != as {
+			return nil
+		}
+		localAS = as
+	}
+
+	return localAS
+}
+
+// StatusIdentity reports the router ID, hostname and local AS
+// of the instance. The local AS is only discoverable from the
+// BGP protocols of BIRD 2 and later.
+func StatusIdentity(opts RunOptions) (Parsed, bool) {
+	status, from_cache := Status(opts)
+	if IsSpecial(status) {
+		return status, from_cache
+	}
+
+	protocols, _ := Protocols(opts)
+	if IsSpecial(protocols) {
+		return protocols, false
+	}
+
+	birdStatus, _ := status["status"].(Parsed)
+	return Parsed{
+		"identity": Parsed{
+			"router_id": birdStatus["router_id"],
+			"hostname":  birdStatus["hostname"],
+			"local_as":  protocolsLocalAS(protocols),
+		},
+		"ttl":       status["ttl"],
+		"cached_at": status["cached_at"],
+	}, from_cache
+}
+
+func Symbols(opts RunOptions) (Parsed, bool) {
+	return RunAndParse(opts, GetCacheKey("Symbols"), "symbols", parseSymbols, nil)
+}
+
+// Select the parser for the output of a raw command.
+// Output of commands without a parser is returned linewise.
+func rawParser(cmd string) func(io.Reader) Parsed {
+	switch {
+	case strings.HasPrefix(cmd, "route ") && strings.HasSuffix(cmd, " count"):
+		return parseRoutesCount
+	case cmd == "route" || strings.HasPrefix(cmd, "route "):
+		return parseRoutes
+	case strings.HasPrefix(cmd, "protocols all"):
+		return parseProtocols
+	case cmd == "protocols" || strings.HasPrefix(cmd, "protocols "):
+		return parseProtocolsShort
+	case cmd == "status":
+		return parseStatus
+	case cmd == "symbols" || strings.HasPrefix(cmd, "symbols "):
+		return parseSymbols
+	case cmd == "interfaces":
+		return parseInterfaces
+	}
+	return parseRawOutput
+}
+
+// The results of raw commands are cached with their own key,
+// as they are not in the shape of the results of the other
+// queries running the same command.
+func rawCommandCacheKey(cmd string) string {
+	return "raw_command " + cmd
+}
+
+// RawCommand runs an arbitrary show command. The command
+// must be validated by the caller. The limits apply to
+// birdc in addition to the global settings.
+func RawCommand(opts RunOptions, cmd string, limits RunLimits) (Parsed, bool) {
+	return runAndParseLimited(
+		limits,
+		defaultCacheTtl(),
+		opts,
+		GetCacheKey("RawCommand", cmd),
+		rawCommandCacheKey(cmd),
+		cmd,
+		rawParser(cmd),
+		nil)
 }
 
 func routesQuery(filter string) string {
+	return routesQueryFamily(filter, IPVersion)
+}
+
+func routesQueryFamily(filter string, ipVersion string) string {
 	cmd := "route " + filter
 
 	if getBirdVersion() < 2 || ClientConf.Dualstack {
 		return cmd
 	}
 
-	return cmd + " where net.type = NET_IP" + IPVersion
+	return cmd + " where net.type = NET_IP" + ipVersion
 }
 
 func remapTable(table string) string {
+	return remapTableFamily(table, IPVersion)
+}
+
+func remapTableFamily(table string, ipVersion string) string {
 	if v := getBirdVersion(); v < 2 {
 		return table // Nothing to do for bird1
 	}
@@ -329,165 +1036,348 @@ func remapTable(table string) string {
 	}
 
 	// Rewrite master table
-	if IPVersion == "4" {
+	if ipVersion == "4" {
 		return "master4"
 	}
 
 	return "master6"
 }
 
-func RoutesPrefixed(useCache bool, prefix string) (Parsed, bool) {
+// TableFamily gets the IP version of the routes in a table,
+// if known. BIRD 1 serves a single family, the default
+// tables of BIRD 2 are master4 and master6.
+func TableFamily(table string) (string, bool) {
+	switch v := getBirdVersion(); {
+	case v == 1:
+		return IPVersion, true
+	case v < 2:
+		return "", false // BIRD is not available
+	}
+
+	switch table {
+	case "master4":
+		return "4", true
+	case "master6":
+		return "6", true
+	}
+	return "", false
+}
+
+// ParseNet parses a network address or prefix and returns
+// it together with its IP version ("4" or "6").
+// IPv4-mapped IPv6 addresses are rewritten to their
+// IPv4 representation.
+func ParseNet(prefix string) (string, string, error) {
+	addr := prefix
+	length := -1
+	if i := strings.Index(prefix, "/"); i >= 0 {
+		addr = prefix[:i]
+		l, err := strconv.Atoi(prefix[i+1:])
+		if err != nil || l < 0 {
+			return "", "", fmt.Errorf("invalid prefix length in net: %s", prefix)
+		}
+		length = l
+	}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return "", "", fmt.Errorf("invalid address in net: %s", prefix)
+	}
+
+	version := "6"
+	maxLength := 128
+	if ip4 := ip.To4(); ip4 != nil {
+		if strings.Contains(addr, ":") && length >= 0 {
+			// IPv4-mapped prefix, e.g. ::ffff:10.0.0.0/104
+			length -= 96
+			if length < 0 {
+				return "", "", fmt.Errorf("invalid prefix length in net: %s", prefix)
+			}
+		}
+		addr = ip4.String()
+		version = "4"
+		maxLength = 32
+	}
+
+	if length > maxLength {
+		return "", "", fmt.Errorf("invalid prefix length in net: %s", prefix)
+	}
+	if length >= 0 {
+		addr += "/" + strconv.Itoa(length)
+	}
+
+	return addr, version, nil
+}
+
+// netFamily returns the normalized net and its IP version.
+// When the net can not be parsed, it is returned as is
+// together with the configured IP version.
+func netFamily(net string) (string, string) {
+	n, v, err := ParseNet(net)
+	if err != nil {
+		return net, IPVersion
+	}
+	return n, v
+}
+
+func RoutesPrefixed(opts RunOptions, prefix string) (Parsed, bool) {
 	cmd := routesQuery(prefix + " all")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesPrefixed", prefix),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
-func RoutesProto(useCache bool, protocol string) (Parsed, bool) {
+// RoutesProto gets all routes of a protocol with their
+// attributes. The result is cached with its own TTL, as
+// the output of large protocols is expensive.
+func RoutesProto(opts RunOptions, protocol string) (Parsed, bool) {
 	cmd := routesQuery("all protocol '" + protocol + "'")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesProto", protocol),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
-func RoutesPeer(useCache bool, peer string) (Parsed, bool) {
+// Get the BIRD protocol, e.g. BGP or Static, of a protocol
+// from the result of Protocols. Empty if it does not exist.
+func protocolType(protocols Parsed, name string) string {
+	var protocol map[string]interface{}
+	switch res := protocols["protocols"].(type) {
+	case Parsed:
+		protocol, _ = res[name].(Parsed)
+	case map[string]interface{}: // decoded from redis
+		protocol, _ = res[name].(map[string]interface{})
+	}
+	birdProtocol, _ := protocol["bird_protocol"].(string)
+	return birdProtocol
+}
+
+// RoutesStatic gets the routes of a static protocol.
+// Other protocols are not found.
+func RoutesStatic(opts RunOptions, protocol string) (Parsed, bool) {
+	protocols, fromCache := Protocols(RunOptions{UseCache: true, Exempt: opts.Exempt})
+	if IsSpecial(protocols) {
+		return protocols, fromCache
+	}
+	if protocolType(protocols, protocol) != "Static" {
+		return NotFound("No such static protocol " + protocol), false
+	}
+
+	cmd := routesQuery("all protocol '" + protocol + "'")
+	return RunAndParse(
+		opts,
+		GetCacheKey("RoutesStatic", protocol),
+		cmd,
+		parseRoutes,
+		nil)
+}
+
+func RoutesPeer(opts RunOptions, peer string) (Parsed, bool) {
 	cmd := "route all where from=" + peer
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesPeer", peer),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
-func RoutesTableAndPeer(useCache bool, table string, peer string) (Parsed, bool) {
+func RoutesTableAndPeer(opts RunOptions, table string, peer string) (Parsed, bool) {
 	table = remapTable(table)
 	cmd := "route table '" + table + "' all where from=" + peer
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesTableAndPeer", table, peer),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
-func RoutesProtoCount(useCache bool, protocol string) (Parsed, bool) {
+// RoutesTableAndOrigin gets the routes of a table originated
+// by the AS, which is the last AS of the path. Routes
+// without a BGP path are not matched by BIRD.
+func RoutesTableAndOrigin(opts RunOptions, table string, as string) (Parsed, bool) {
+	table = remapTable(table)
+	cmd := routesQuery("table '" + table + "' all where bgp_path.last = " + as)
+	return RunAndParse(
+		opts,
+		GetCacheKey("RoutesTableAndOrigin", table, as),
+		cmd,
+		parseRoutes,
+		nil)
+}
+
+func RoutesProtoCount(opts RunOptions, protocol string) (Parsed, bool) {
 	cmd := routesQuery("protocol '" + protocol + "' count")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesProtoCount", protocol),
 		cmd,
 		parseRoutesCount,
 		nil)
 }
 
-func RoutesProtoPrimaryCount(useCache bool, protocol string) (Parsed, bool) {
+func RoutesProtoPrimaryCount(opts RunOptions, protocol string) (Parsed, bool) {
 	cmd := routesQuery("primary protocol '" + protocol + "' count")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesProtoPrimaryCount", protocol),
 		cmd,
 		parseRoutesCount,
 		nil)
 }
 
-func PipeRoutesFilteredCount(useCache bool, pipe string, table string, neighborAddress string) (Parsed, bool) {
+func PipeRoutesFilteredCount(opts RunOptions, pipe string, table string, neighborAddress string) (Parsed, bool) {
 	table = remapTable(table)
 	cmd := "route table '" + table +
 		"' noexport '" + pipe +
 		"' where from=" + neighborAddress + " count"
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("PipeRoutesFilteredCount", table, pipe, neighborAddress),
 		cmd,
 		parseRoutesCount,
 		nil)
 }
 
-func PipeRoutesFiltered(useCache bool, pipe string, table string) (Parsed, bool) {
+func PipeRoutesFiltered(opts RunOptions, pipe string, table string) (Parsed, bool) {
 	table = remapTable(table)
 	cmd := routesQuery("table '" + table + "' noexport '" + pipe + "' all")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("PipeRoutesFiltered", table, pipe),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
-func RoutesFiltered(useCache bool, protocol string) (Parsed, bool) {
+func RoutesFiltered(opts RunOptions, protocol string) (Parsed, bool) {
 	cmd := routesQuery("all filtered protocol '" + protocol + "'")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesFiltered", protocol),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
-func RoutesExport(useCache bool, protocol string) (Parsed, bool) {
+func RoutesExport(opts RunOptions, protocol string) (Parsed, bool) {
 	cmd := routesQuery("all export '" + protocol + "'")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesExport", protocol),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
-func RoutesNoExport(useCache bool, protocol string) (Parsed, bool) {
+// RoutesTableExport gets the routes of a table, which
+// are exported to the protocol.
+func RoutesTableExport(opts RunOptions, table string, protocol string) (Parsed, bool) {
+	table = remapTable(table)
+	cmd := routesQuery("table '" + table + "' all export '" + protocol + "'")
+	return RunAndParse(
+		opts,
+		GetCacheKey("RoutesTableExport", table, protocol),
+		cmd,
+		parseRoutes,
+		nil)
+}
+
+// Get the reason why routes are not exported to a protocol.
+// BIRD does not report a reason per route, but the routes
+// are rejected by the export filter of the protocol.
+// Without a rejecting filter, no reason is known.
+func noExportReason(protocol Parsed) Parsed {
+	filter, ok := protocol["output_filter"].(string)
+	if !ok || filter == "" || filter == "ACCEPT" {
+		return nil
+	}
+	return Parsed{
+		"protocol": protocol["protocol"],
+		"filter":   filter,
+	}
+}
+
+// Add the noexport reason to all routes
+func setNoExportReasons(routes Parsed, protocol Parsed) {
+	reason := noExportReason(protocol)
+	if reason == nil {
+		return
+	}
+
+	list, ok := routes["routes"].([]Parsed)
+	if !ok {
+		return
+	}
+	for _, route := range list {
+		route["noexport_reason"] = reason
+	}
+}
+
+func RoutesNoExport(opts RunOptions, protocol string) (Parsed, bool) {
 	cmd := routesQuery("all noexport '" + protocol + "'")
+	addReasons := func(p *Parsed) {
+		protocols, _ := Protocols(RunOptions{UseCache: true, Exempt: opts.Exempt})
+		if IsSpecial(protocols) {
+			return
+		}
+		res, _ := protocols["protocols"].(Parsed)
+		if proto, ok := res[protocol].(Parsed); ok {
+			setNoExportReasons(*p, proto)
+		}
+	}
+
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesNoExport", protocol),
 		cmd,
 		parseRoutes,
-		nil)
+		addReasons)
 }
 
-func RoutesExportCount(useCache bool, protocol string) (Parsed, bool) {
+func RoutesExportCount(opts RunOptions, protocol string) (Parsed, bool) {
 	cmd := routesQuery("export '" + protocol + "' count")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesExportCount", protocol),
 		cmd,
 		parseRoutesCount,
 		nil)
 }
 
-func RoutesTable(useCache bool, table string) (Parsed, bool) {
+func RoutesTable(opts RunOptions, table string) (Parsed, bool) {
 	table = remapTable(table)
 	cmd := routesQuery("table '" + table + "' all")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesTable", table),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
-func RoutesTableFiltered(useCache bool, table string) (Parsed, bool) {
+func RoutesTableFiltered(opts RunOptions, table string) (Parsed, bool) {
 	table = remapTable(table)
 	cmd := routesQuery("table '" + table + "' all filtered")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesTableFiltered", table),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
-func RoutesTableCount(useCache bool, table string) (Parsed, bool) {
+func RoutesTableCount(opts RunOptions, table string) (Parsed, bool) {
 	table = remapTable(table)
 	cmd := routesQuery("table '" + table + "' count")
 	return RunAndParse(
-		useCache,
+		opts,
 		GetCacheKey("RoutesTableCount", table),
 		cmd,
 		parseRoutesCount,
@@ -495,27 +1385,386 @@ func RoutesTableCount(useCache bool, table string) (Parsed, bool) {
 	)
 }
 
-func RoutesLookupTable(useCache bool, net string, table string) (Parsed, bool) {
+func RoutesTableAndPeerCount(opts RunOptions, table string, peer string) (Parsed, bool) {
 	table = remapTable(table)
-	cmd := routesQuery("for " + net + " table '" + table + "' all")
+	cmd := routesQuery("table '" + table + "' where from=" + peer + " count")
 	return RunAndParse(
-		useCache,
+		opts,
+		GetCacheKey("RoutesTableAndPeerCount", table, peer),
+		cmd,
+		parseRoutesCount,
+		nil)
+}
+
+func RoutesTablePrimaryCount(opts RunOptions, table string) (Parsed, bool) {
+	table = remapTable(table)
+	cmd := routesQuery("table '" + table + "' primary count")
+	return RunAndParse(
+		opts,
+		GetCacheKey("RoutesTablePrimaryCount", table),
+		cmd,
+		parseRoutesCount,
+		nil)
+}
+
+func Memory(opts RunOptions) (Parsed, bool) {
+	return RunAndParse(opts, GetCacheKey("Memory"), "memory", parseMemory, nil)
+}
+
+// Interfaces gets the interfaces known to BIRD
+// with their state and addresses.
+func Interfaces(opts RunOptions) (Parsed, bool) {
+	return RunAndParse(opts, GetCacheKey("Interfaces"), "interfaces", parseInterfaces, nil)
+}
+
+// OspfLsadb gets the link-state database of
+// the OSPF protocol.
+func OspfLsadb(opts RunOptions) (Parsed, bool) {
+	return RunAndParse(
+		opts,
+		GetCacheKey("OspfLsadb"),
+		"ospf lsadb",
+		parseOspfLsadb,
+		nil)
+}
+
+// Rip gets the interfaces and neighbors of the RIP
+// protocols. No protocols are listed without RIP.
+func Rip(opts RunOptions) (Parsed, bool) {
+	interfaces, from_cache := RunAndParse(
+		opts, GetCacheKey("RipInterfaces"), "rip interfaces", parseRipInterfaces, nil)
+	if IsSpecial(interfaces) {
+		return interfaces, from_cache
+	}
+
+	neighbors, neighborsFromCache := RunAndParse(
+		opts, GetCacheKey("RipNeighbors"), "rip neighbors", parseRipNeighbors, nil)
+	if IsSpecial(neighbors) {
+		return neighbors, false
+	}
+
+	res := ripProtocols(interfaces, neighbors)
+	res["ttl"] = interfaces["ttl"]
+	res["cached_at"] = interfaces["cached_at"]
+	return res, from_cache && neighborsFromCache
+}
+
+// RoutesTableMemory reports the number of routes and networks
+// in a table. BIRD does not report the memory usage per table,
+// so the memory usage of all tables is included.
+func RoutesTableMemory(opts RunOptions, table string) (Parsed, bool) {
+	count, from_cache := RoutesTableCount(opts, table)
+	if IsSpecial(count) {
+		return count, from_cache
+	}
+
+	memory, _ := Memory(opts)
+	if IsSpecial(memory) {
+		return memory, false
+	}
+
+	return Parsed{
+		"table":     remapTable(table),
+		"routes":    count["routes"],
+		"networks":  count["networks"],
+		"memory":    memory["memory"],
+		"ttl":       count["ttl"],
+		"cached_at": count["cached_at"],
+	}, from_cache
+}
+
+func RoutesLookupTable(opts RunOptions, net string, table string) (Parsed, bool) {
+	net, ipVersion := netFamily(net)
+	table = remapTableFamily(table, ipVersion)
+	cmd := routesQueryFamily("for "+net+" table '"+table+"' all", ipVersion)
+	return RunAndParse(
+		opts,
 		GetCacheKey("RoutesLookupTable", net, table),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
-func RoutesLookupProtocol(useCache bool, net string, protocol string) (Parsed, bool) {
-	cmd := routesQuery("for " + net + " protocol '" + protocol + "' all")
+// RoutesLookupTablePeer gets the routes for a net in
+// the table, which were learnt from the peer.
+func RoutesLookupTablePeer(opts RunOptions, net string, table string, peer string) (Parsed, bool) {
+	net, ipVersion := netFamily(net)
+	table = remapTableFamily(table, ipVersion)
+	cmd := routesQueryFamily("for "+net+" table '"+table+"' all where from="+peer, ipVersion)
 	return RunAndParse(
-		useCache,
+		opts,
+		GetCacheKey("RoutesLookupTablePeer", net, table, peer),
+		cmd,
+		parseRoutes,
+		nil)
+}
+
+// RoutesLookupTableProtocol gets the routes for a net
+// in the table, which were learnt by the protocol.
+func RoutesLookupTableProtocol(opts RunOptions, net string, table string, protocol string) (Parsed, bool) {
+	net, ipVersion := netFamily(net)
+	table = remapTableFamily(table, ipVersion)
+	cmd := routesQueryFamily("for "+net+" table '"+table+"' all protocol '"+protocol+"'", ipVersion)
+	return RunAndParse(
+		opts,
+		GetCacheKey("RoutesLookupTableProtocol", net, table, protocol),
+		cmd,
+		parseRoutes,
+		nil)
+}
+
+// RoutesLookupAddr gets the best route of the longest
+// matching network for an address in the table.
+func RoutesLookupAddr(opts RunOptions, addr string, table string) (Parsed, bool) {
+	addr, ipVersion := netFamily(addr)
+	table = remapTableFamily(table, ipVersion)
+	cmd := routesQueryFamily("for "+addr+" table '"+table+"' primary all", ipVersion)
+	return RunAndParse(
+		opts,
+		GetCacheKey("RoutesLookupAddr", addr, table),
+		cmd,
+		parseRoutes,
+		nil)
+}
+
+func RoutesLookupProtocol(opts RunOptions, net string, protocol string) (Parsed, bool) {
+	net, ipVersion := netFamily(net)
+	cmd := routesQueryFamily("for "+net+" protocol '"+protocol+"' all", ipVersion)
+	return RunAndParse(
+		opts,
 		GetCacheKey("RoutesLookupProtocol", net, protocol),
 		cmd,
 		parseRoutes,
 		nil)
 }
 
+// Get the names of all routing tables from the symbols
+func routingTables(symbols Parsed) []string {
+	res, _ := symbols["symbols"].(Parsed)
+	switch tables := res["routing table"].(type) {
+	case []string:
+		return tables
+	case []interface{}: // decoded from the redis cache
+		names := make([]string, 0, len(tables))
+		for _, table := range tables {
+			if name, ok := table.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return []string{}
+}
+
+// The maximum of concurrent lookups run by a request,
+// defaults to the worker pool size. Identical commands of
+// concurrent lookups are run once, see RunQueue.
+func maxConcurrentLookups() int {
+	if ClientConf.MaxConcurrentLookups > 0 {
+		return ClientConf.MaxConcurrentLookups
+	}
+	return WorkerPoolSize
+}
+
+// RoutesLookupTables looks up a net in all routing tables
+// and returns the routes by table. Tables without routes
+// for the net are omitted. At most maxConcurrentLookups
+// lookups are running concurrently.
+func RoutesLookupTables(opts RunOptions, net string) (Parsed, bool) {
+	symbols, from_cache := Symbols(opts)
+	if IsSpecial(symbols) {
+		return symbols, from_cache
+	}
+	tables := routingTables(symbols)
+
+	type lookup struct {
+		res       Parsed
+		fromCache bool
+	}
+	results := make([]lookup, len(tables))
+
+	wg := &sync.WaitGroup{}
+	slots := make(chan struct{}, maxConcurrentLookups())
+	for i, table := range tables {
+		wg.Add(1)
+		go func(i int, table string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			res, fromCache := RoutesLookupTable(opts, net, table)
+			results[i] = lookup{res, fromCache}
+		}(i, table)
+	}
+	wg.Wait()
+
+	routes := Parsed{}
+	for i, table := range tables {
+		if isNotFound(results[i].res) {
+			continue // The table was removed meanwhile
+		}
+		if IsSpecial(results[i].res) {
+			return results[i].res, false
+		}
+		from_cache = from_cache && results[i].fromCache
+
+		switch r := results[i].res["routes"].(type) {
+		case []Parsed:
+			if len(r) > 0 {
+				routes[table] = r
+			}
+		case []interface{}:
+			if len(r) > 0 {
+				routes[table] = r
+			}
+		}
+	}
+
+	return Parsed{
+		"tables":    routes,
+		"ttl":       symbols["ttl"],
+		"cached_at": symbols["cached_at"],
+	}, from_cache
+}
+
+// RoutesTablesCount counts the routes of all routing
+// tables. At most maxConcurrentLookups counts are running
+// concurrently.
+func RoutesTablesCount(opts RunOptions) (Parsed, bool) {
+	symbols, from_cache := Symbols(opts)
+	if IsSpecial(symbols) {
+		return symbols, from_cache
+	}
+	tables := routingTables(symbols)
+
+	type count struct {
+		res       Parsed
+		fromCache bool
+	}
+	results := make([]count, len(tables))
+
+	wg := &sync.WaitGroup{}
+	slots := make(chan struct{}, maxConcurrentLookups())
+	for i, table := range tables {
+		wg.Add(1)
+		go func(i int, table string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			res, fromCache := RoutesTableCount(opts, table)
+			results[i] = count{res, fromCache}
+		}(i, table)
+	}
+	wg.Wait()
+
+	counts := Parsed{}
+	for i, table := range tables {
+		if isNotFound(results[i].res) {
+			continue // The table was removed meanwhile
+		}
+		if IsSpecial(results[i].res) {
+			return results[i].res, false
+		}
+		from_cache = from_cache && results[i].fromCache
+		if routes, ok := results[i].res["routes"]; ok {
+			counts[table] = routes
+		}
+	}
+
+	return Parsed{
+		"tables":    counts,
+		"ttl":       symbols["ttl"],
+		"cached_at": symbols["cached_at"],
+	}, from_cache
+}
+
+// RoutesLookupExportCount counts the routes of a net
+// exported to the protocol.
+func RoutesLookupExportCount(opts RunOptions, net string, protocol string) (Parsed, bool) {
+	net, ipVersion := netFamily(net)
+	cmd := routesQueryFamily(net+" export '"+protocol+"' count", ipVersion)
+	return RunAndParse(
+		opts,
+		GetCacheKey("RoutesLookupExportCount", net, protocol),
+		cmd,
+		parseRoutesCount,
+		nil)
+}
+
+// Get the names of the established BGP protocols
+// from the terse protocols list, sorted by name.
+func establishedBgpProtocols(protocols Parsed) []string {
+	names := []string{}
+	for name, protocol := range shortProtocols(protocols) {
+		if protocol["proto"] == "BGP" && protocol["state"] == "up" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RoutesLookupExports gets the established BGP protocols the
+// net is exported to. At most maxConcurrentLookups lookups are
+// running concurrently.
+func RoutesLookupExports(opts RunOptions, net string) (Parsed, bool) {
+	protocols, from_cache := ProtocolsShort(opts)
+	if IsSpecial(protocols) {
+		return protocols, from_cache
+	}
+	names := establishedBgpProtocols(protocols)
+
+	type lookup struct {
+		res       Parsed
+		fromCache bool
+	}
+	results := make([]lookup, len(names))
+
+	wg := &sync.WaitGroup{}
+	slots := make(chan struct{}, maxConcurrentLookups())
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			res, fromCache := RoutesLookupExportCount(opts, net, name)
+			results[i] = lookup{res, fromCache}
+		}(i, name)
+	}
+	wg.Wait()
+
+	exports := []string{}
+	for i, name := range names {
+		if isNotFound(results[i].res) {
+			continue // The protocol was removed meanwhile
+		}
+		if IsSpecial(results[i].res) {
+			return results[i].res, false
+		}
+		from_cache = from_cache && results[i].fromCache
+
+		switch count := results[i].res["routes"].(type) {
+		case int64:
+			if count > 0 {
+				exports = append(exports, name)
+			}
+		case float64: // decoded from the redis cache
+			if count > 0 {
+				exports = append(exports, name)
+			}
+		}
+	}
+
+	return Parsed{
+		"protocols": exports,
+		"ttl":       protocols["ttl"],
+		"cached_at": protocols["cached_at"],
+	}, from_cache
+}
+
 func getBirdVersion() int {
 	// We assume the bird major version does not change during
 	// the time the birdwatcher is running.
@@ -527,7 +1776,7 @@ func getBirdVersion() int {
 	}
 
 	// This method is a bit hacky.
-	status, _ := Status(false) // Get status without cache
+	status, _ := Status(RunOptions{}) // Get status without cache
 	if IsSpecial(status) {
 		return 0
 	}
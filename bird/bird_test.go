@@ -0,0 +1,482 @@
+package bird
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/metrics"
+)
+
+func TestParseNet(t *testing.T) {
+	tests := []struct {
+		net       string
+		expected  string
+		ipVersion string
+	}{
+		{"10.0.0.0/8", "10.0.0.0/8", "4"},
+		{"10.1.2.3", "10.1.2.3", "4"},
+		{"2001:db8::/32", "2001:db8::/32", "6"},
+		{"2001:db8::1", "2001:db8::1", "6"},
+		{"::ffff:10.1.2.3", "10.1.2.3", "4"},
+		{"::ffff:10.0.0.0/104", "10.0.0.0/8", "4"},
+	}
+
+	for _, test := range tests {
+		net, ipVersion, err := ParseNet(test.net)
+		if err != nil {
+			t.Error(test.net, "should be a valid net:", err)
+			continue
+		}
+		if net != test.expected || ipVersion != test.ipVersion {
+			t.Error("Parse net:", test.net, "got:", net, ipVersion,
+				"expected:", test.expected, test.ipVersion)
+		}
+	}
+
+	invalid := []string{
+		"10.0.0.0/33",
+		"2001:db8::/129",
+		"::ffff:10.0.0.0/64",
+		"10.0.0/8",
+		"10.0.0.0/",
+		"foo",
+	}
+	for _, net := range invalid {
+		if _, _, err := ParseNet(net); err == nil {
+			t.Error(net, "should be an invalid net")
+		}
+	}
+}
+
+func TestProtocolsLocalAS(t *testing.T) {
+	protocols := Parsed{"protocols": Parsed{
+		"R1":     Parsed{"bird_protocol": "BGP", "local_as": int64(65000)},
+		"R2":     Parsed{"bird_protocol": "BGP", "local_as": int64(65000)},
+		"device": Parsed{"bird_protocol": "Device"},
+	}}
+	if as := protocolsLocalAS(protocols); as != int64(65000) {
+		t.Error("Expected local AS 65000, got:", as)
+	}
+
+	protocols["protocols"].(Parsed)["R3"] = Parsed{"bird_protocol": "BGP", "local_as": int64(65001)}
+	if as := protocolsLocalAS(protocols); as != nil {
+		t.Error("Expected no local AS for different local ASes, got:", as)
+	}
+}
+
+func TestIsNotReady(t *testing.T) {
+	tests := []struct {
+		file     string
+		notReady bool
+	}{
+		{"reconfigure_in_progress.sample", true},
+		{"shutdown_in_progress.sample", true},
+		{"status2.sample", false},
+		{"routes_bird2_ipv4.sample", false},
+		{"protocols_short.sample", false},
+	}
+	for _, test := range tests {
+		out, err := ioutil.ReadFile("../test/" + test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isNotReady(out) != test.notReady {
+			t.Error("Expected not ready to be", test.notReady, "for", test.file)
+		}
+	}
+}
+
+func TestNotFoundReply(t *testing.T) {
+	tests := []struct {
+		file    string
+		message string
+	}{
+		{"no_such_protocol.sample", "No such protocol R192_999"},
+		{"not_a_table.sample", "master6 is not a table"},
+		{"undefined_symbol.sample", "syntax error, unexpected CF_SYM_UNDEFINED, expecting CF_SYM_KNOWN"},
+		{"reconfigure_in_progress.sample", ""},
+		{"routes_bird2_ipv4.sample", ""},
+		{"protocols_short.sample", ""},
+	}
+	for _, test := range tests {
+		out, err := ioutil.ReadFile("../test/" + test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		message, ok := notFoundReply(out)
+		if ok != (test.message != "") || message != test.message {
+			t.Error("Expected not found reply", test.message, "for", test.file, "got:", message)
+		}
+	}
+}
+
+func TestNotFoundIsSpecial(t *testing.T) {
+	res := NotFound("No such protocol R192_999")
+	if !IsSpecial(res) {
+		t.Error("Expected not found result to be special")
+	}
+	if res[ErrorStatusKey] != 404 {
+		t.Error("Expected status 404, got:", res[ErrorStatusKey])
+	}
+	if IsSpecial(Parsed{"routes": []Parsed{}}) {
+		t.Error("Expected empty routes not to be special")
+	}
+}
+
+func TestSetNoExportReasons(t *testing.T) {
+	f, err := openFile("protocols_bgp_pipe.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	protocols := parseProtocols(f)["protocols"].(Parsed)
+	f.Close()
+
+	f, err = openFile("routes_bird2_ipv4.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := parseRoutes(f)
+	f.Close()
+
+	// Routes rejected by an export filter
+	setNoExportReasons(routes, protocols["C65003_nada2_co_ripe"].(Parsed))
+	expected := Parsed{
+		"protocol": "C65003_nada2_co_ripe",
+		"filter":   "REJECT",
+	}
+	for _, route := range routes["routes"].([]Parsed) {
+		if !reflect.DeepEqual(route["noexport_reason"], expected) {
+			t.Error("Expected noexport_reason to be", expected, "not", route["noexport_reason"])
+		}
+	}
+
+	// Without a rejecting export filter, the reason is unknown
+	f, err = openFile("routes_bird2_ipv4.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes = parseRoutes(f)
+	f.Close()
+
+	setNoExportReasons(routes, Parsed{"protocol": "R1", "output_filter": "ACCEPT"})
+	for _, route := range routes["routes"].([]Parsed) {
+		if _, ok := route["noexport_reason"]; ok {
+			t.Error("Expected no noexport_reason, got:", route["noexport_reason"])
+		}
+	}
+}
+
+func TestRunLimited(t *testing.T) {
+	f, err := openFile("status1.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sample, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runLimited(helperCommand("status1.sample"), int64(len(sample)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(sample) {
+		t.Error("Unexpected output:", string(out))
+	}
+
+	_, err = runLimited(helperCommand("status1.sample"), int64(len(sample)-1))
+	if err != ErrOutputTooLarge {
+		t.Error("Expected ErrOutputTooLarge, got:", err)
+	}
+}
+
+func TestRoutingTables(t *testing.T) {
+	symbols := Parsed{"symbols": Parsed{
+		"routing table": []string{"master4", "master6", "T65001"},
+		"protocol":      []string{"R1"},
+	}}
+	if tables := routingTables(symbols); !reflect.DeepEqual(tables, []string{"master4", "master6", "T65001"}) {
+		t.Error("Unexpected routing tables:", tables)
+	}
+
+	// Symbols decoded from the redis cache
+	symbols = Parsed{"symbols": Parsed{
+		"routing table": []interface{}{"master4"},
+	}}
+	if tables := routingTables(symbols); !reflect.DeepEqual(tables, []string{"master4"}) {
+		t.Error("Unexpected routing tables:", tables)
+	}
+}
+
+func TestNegativeCacheTtl(t *testing.T) {
+	if !isNegativeResult(Parsed{"routes": []Parsed{}}) {
+		t.Error("Expected result without routes to be negative")
+	}
+	if isNegativeResult(Parsed{"routes": []Parsed{{"network": "10.0.0.0/8"}}}) {
+		t.Error("Expected result with routes not to be negative")
+	}
+	if isNegativeResult(Parsed{"routes": int64(0)}) {
+		t.Error("Expected route count not to be negative")
+	}
+
+	if ttl := moduleCacheTtl(negativeTtlKey, 5); ttl != 5 {
+		t.Error("Expected the TTL of the command without negative TTL, got:", ttl)
+	}
+	CacheConf.Ttl = map[string]int{"negative": 1}
+	defer func() { CacheConf.Ttl = nil }()
+	if ttl := moduleCacheTtl(negativeTtlKey, 5); ttl != 1 {
+		t.Error("Expected the negative TTL, got:", ttl)
+	}
+}
+
+func TestProtocolType(t *testing.T) {
+	f, err := openFile("protocols_bird2_channels.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	protocols := parseProtocols(f)
+	f.Close()
+
+	if protocolType(protocols, "kernel1") != "Kernel" {
+		t.Error("Expected kernel1 to be a Kernel protocol")
+	}
+	if protocolType(protocols, "static1") != "" {
+		t.Error("Expected no type for a missing protocol")
+	}
+
+	// Protocols decoded from redis
+	decoded := Parsed{"protocols": map[string]interface{}{
+		"static1": map[string]interface{}{"bird_protocol": "Static"},
+	}}
+	if protocolType(decoded, "static1") != "Static" {
+		t.Error("Expected static1 to be a Static protocol")
+	}
+}
+
+func TestParseRoutesStatic(t *testing.T) {
+	f, err := openFile("routes_bird2_static.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	routes := parseRoutes(f)["routes"].([]Parsed)
+	if len(routes) != 2 {
+		t.Fatal("Expected 2 static routes, got:", len(routes))
+	}
+	if routes[0]["from_protocol"] != "static1" || routes[0]["gateway"] != "192.0.2.1" {
+		t.Error("Unexpected static route:", routes[0])
+	}
+	if routes[1]["network"] != "192.0.2.128/25" {
+		t.Error("Unexpected blackhole route:", routes[1])
+	}
+}
+
+func TestCountParseErrors(t *testing.T) {
+	f, err := openFile("routes_bird2_multipath.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Failed lines are counted without reporting them
+	parsed := parseRoutes(io.MultiReader(f, strings.NewReader(
+		"\tUnknown.attribute: 1\n\tUnknown.attribute: 2\n")))
+	if parsed[parseErrorCountKey] != 2 {
+		t.Fatal("Expected 2 failed lines, got:", parsed[parseErrorCountKey])
+	}
+
+	countParseErrors(GetCacheKey("RoutesTable", "master4"), parsed)
+	if _, ok := parsed[parseErrorCountKey]; ok {
+		t.Error("Expected the count to be removed")
+	}
+
+	buf := &bytes.Buffer{}
+	metrics.WritePrometheus(buf)
+	if !strings.Contains(buf.String(), `birdwatcher_parse_errors_total{endpoint="routestable"} 2`) {
+		t.Error("Expected the parse errors in the metrics:", buf.String())
+	}
+}
+
+func TestParseProtocolsInvalidHeader(t *testing.T) {
+	parsed := parseProtocols(strings.NewReader("BIRD 2.0.7 ready.\nsomething unexpected\n  Preference: 100\n\n"))
+	if len(parsed["protocols"].(Parsed)) != 0 {
+		t.Error("Expected no protocols, got:", parsed["protocols"])
+	}
+	if parsed[parseErrorCountKey] != 1 {
+		t.Error("Expected the protocol to fail, got:", parsed[parseErrorCountKey])
+	}
+}
+
+func TestEstablishedBgpProtocols(t *testing.T) {
+	f, err := openFile("protocols_short.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	names := establishedBgpProtocols(parseProtocolsShort(f))
+	if len(names) != 9 {
+		t.Fatal("Expected 9 established BGP protocols, got:", names)
+	}
+	for _, name := range names {
+		if name == "R194_205" || name == "pp_0097_as3856" {
+			t.Error("Expected only established BGP protocols, got:", name)
+		}
+	}
+	if names[0] != "R192_175" {
+		t.Error("Expected sorted names, got:", names)
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	cmd := shellCommand("birdc", []string{"-s", "/run/bird.ctl", "-r", "show", "route", "table", "'master4'", "where", "from=10.0.0.1"})
+	expected := `birdc -s /run/bird.ctl -r show route table ''\''master4'\''' where from=10.0.0.1`
+	if cmd != expected {
+		t.Error("Expected", expected, "got:", cmd)
+	}
+	if cmd := shellCommand("birdc", []string{"", "a b"}); cmd != `birdc '' 'a b'` {
+		t.Error("Expected empty and spaced arguments to be quoted, got:", cmd)
+	}
+}
+
+// Fake birdc run by the tests as the test binary, see
+// helperBirdc. It prints the sample in the environment.
+func TestHelperBirdc(t *testing.T) {
+	sample := os.Getenv("BIRDWATCHER_TEST_SAMPLE")
+	if sample == "" {
+		return
+	}
+	f, err := openFile(sample)
+	if err != nil {
+		os.Exit(1)
+	}
+	io.Copy(os.Stdout, f)
+	os.Exit(0)
+}
+
+// Get a command running the fake birdc, which
+// prints the sample.
+func helperCommand(sample string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperBirdc$", "--")
+	cmd.Env = append(os.Environ(), "BIRDWATCHER_TEST_SAMPLE="+sample)
+	return cmd
+}
+
+// Use the fake birdc printing the sample. The returned
+// function restores the config.
+func helperBirdc(sample string) func() {
+	os.Setenv("BIRDWATCHER_TEST_SAMPLE", sample)
+	ClientConf.BirdCmd = os.Args[0] + " -test.run=^TestHelperBirdc$ --"
+	BirdVersion = 1
+	return func() {
+		os.Unsetenv("BIRDWATCHER_TEST_SAMPLE")
+		ClientConf = BirdConfig{}
+		BirdVersion = 0
+	}
+}
+
+func TestRoutesProtoTtl(t *testing.T) {
+	defer helperBirdc("routes_bird1_ipv4.sample")()
+	CacheConf.Ttl = map[string]int{"routes_protocol": 7}
+	defer func() { CacheConf.Ttl = nil }()
+
+	saved := cache
+	cache = NewMemoryCache(100)
+	defer func() { cache = saved }()
+
+	res, _ := RoutesProto(RunOptions{Exempt: true, Module: "routes_protocol"}, "R1")
+	if routes, _ := res["routes"].([]Parsed); len(routes) == 0 {
+		t.Fatal("Expected routes, got:", res)
+	}
+	ttl, _ := res["ttl"].(time.Time)
+	if d := time.Until(ttl); d < 6*time.Minute || d > 7*time.Minute {
+		t.Error("Expected the routes to be cached for 7 minutes, got:", d)
+	}
+}
+
+func TestRawOutput(t *testing.T) {
+	defer helperBirdc("routes_bird1_ipv4.sample")()
+	ClientConf.CacheTtl = 5
+	ParserConf.RawOutput = true
+	defer func() { ParserConf.RawOutput = false }()
+
+	saved := cache
+	cache = NewMemoryCache(100)
+	defer func() { cache = saved }()
+
+	res, _ := RoutesProto(RunOptions{Exempt: true}, "R1")
+	if _, ok := res["_raw"]; ok {
+		t.Error("Expected no raw output if not requested")
+	}
+
+	res, fromCache := RoutesProto(RunOptions{UseCache: true, Raw: true}, "R1")
+	if raw, _ := res["_raw"].(string); !fromCache || raw == "" {
+		t.Error("Expected the cached raw output, got:", res["_raw"])
+	}
+
+	// The raw output is not cached with the result
+	res, _ = RoutesProto(RunOptions{UseCache: true}, "R1")
+	if _, ok := res["_raw"]; ok {
+		t.Error("Expected the cached result without raw output")
+	}
+}
+
+func TestRoutesLookupTableProtocol(t *testing.T) {
+	defer helperBirdc("routes_bird1_ipv4.sample")()
+	ClientConf.CacheTtl = 5
+
+	saved := cache
+	cache = NewMemoryCache(100)
+	defer func() { cache = saved }()
+
+	res, _ := RoutesLookupTableProtocol(RunOptions{}, "10.0.0.0/8", "master", "R1")
+	if routes, _ := res["routes"].([]Parsed); len(routes) == 0 {
+		t.Fatal("Expected routes, got:", res)
+	}
+	if _, ok := fromCache("route for 10.0.0.0/8 table 'master' all protocol 'R1'"); !ok {
+		t.Error("Expected the routes of the protocol to be queried")
+	}
+}
+
+func TestMaxConcurrentBirdc(t *testing.T) {
+	ClientConf.MaxConcurrentBirdc = 1
+	defer func() { ClientConf = BirdConfig{} }()
+
+	acquireBirdc()
+	acquired := make(chan struct{})
+	go func() {
+		acquireBirdc()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Expected birdc to wait for a slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	releaseBirdc()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("Expected birdc to run after the slot was released")
+	}
+	releaseBirdc()
+
+	if n := maxConcurrentLookups(); n != WorkerPoolSize {
+		t.Error("Expected the worker pool size by default, got:", n)
+	}
+	ClientConf.MaxConcurrentLookups = 2
+	if n := maxConcurrentLookups(); n != 2 {
+		t.Error("Expected 2 concurrent lookups, got:", n)
+	}
+}
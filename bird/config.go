@@ -9,22 +9,52 @@ type StatusConfig struct {
 	FilterFields []string `toml:"filter_fields"`
 }
 
+type ListenerConfig struct {
+	Address string `toml:"address"`
+	TLS     bool   `toml:"tls"`
+}
+
 type BirdConfig struct {
 	Listen         string
-	ConfigFilename string `toml:"config"`
-	BirdCmd        string `toml:"birdc"`
-	CacheTtl       int    `toml:"ttl"`
-	Dualstack      bool   `toml:"dualstack"`
+	Listeners      []ListenerConfig `toml:"listeners"`
+	ConfigFilename string           `toml:"config"`
+	ExposedConfig  string           `toml:"exposed_config"`
+	BirdCmd        string           `toml:"birdc"`
+	CacheTtl       int              `toml:"ttl"`
+	Dualstack      bool             `toml:"dualstack"`
+	MaxOutputBytes int64            `toml:"max_output_bytes"`
+
+	// Either "warn" or "fail" if birdc is not usable
+	// at startup, "off" skips the check.
+	StartupCheck       string `toml:"startup_check"`
+	StartupCheckStatus bool   `toml:"startup_check_status"`
+
+	// Log the command line of each birdc run for debugging
+	LogCommands bool `toml:"log_commands"`
+
+	// Limits of concurrent birdc runs overall and of the
+	// lookups run by a single request, e.g. in all tables
+	MaxConcurrentBirdc   int `toml:"max_concurrent_birdc"`
+	MaxConcurrentLookups int `toml:"max_concurrent_lookups"`
 }
 
 type ParserConfig struct {
 	FilterFields []string `toml:"filter_fields"`
+	RawOutput    bool     `toml:"raw_output"`
+
+	ReportParseErrors bool `toml:"report_parse_errors"`
+	DebugTiming       bool `toml:"debug_timing"`
 }
 
 type RateLimitConfig struct {
 	Reqs    int
 	Max     int `toml:"requests_per_minute"`
 	Enabled bool
+
+	// Requests authorized with one of these bearer tokens
+	// or from one of these IPs or CIDRs are not limited.
+	ExemptTokens []string `toml:"exempt_tokens"`
+	ExemptFrom   []string `toml:"exempt_from"`
 }
 
 type CacheConfig struct {
@@ -33,5 +63,25 @@ type CacheConfig struct {
 	RedisPassword string `toml:"redis_password"`
 	RedisDb       int    `toml:"redis_db"`
 
+	RedisRetries       int  `toml:"redis_retries"`
+	RedisRetryInterval int  `toml:"redis_retry_interval"`
+	RedisFallback      bool `toml:"redis_fallback"`
+	RedisHashKeys      bool `toml:"redis_hash_keys"`
+
+	// Memory tier in front of redis, the TTL is in seconds
+	MemoryTierKeys int `toml:"memory_tier_keys"`
+	MemoryTierTtl  int `toml:"memory_tier_ttl"`
+
 	MaxKeys int `toml:"max_keys"`
+
+	StaleWhileError int `toml:"stale_while_error"`
+
+	TtlJitter float64 `toml:"ttl_jitter"`
+
+	// TTLs (in minutes) by module, overriding the ttl
+	// of the [bird] section
+	Ttl map[string]int `toml:"ttl"`
+
+	PersistFile     string `toml:"persist_file"`
+	PersistInterval int    `toml:"persist_interval"`
 }
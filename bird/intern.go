@@ -0,0 +1,71 @@
+package bird
+
+// Values in large routing tables, like protocol names,
+// next hops and AS paths, are repeated for many routes.
+// Interning them lets the routes share the strings.
+
+// Intern the strings of parsed routes. Disabled
+// only to measure the savings in benchmarks.
+var internStrings = true
+
+// stringInterner deduplicates strings. It is not safe for
+// concurrent use, each route parsing worker has its own.
+type stringInterner map[string]string
+
+// Copy a string, so it does not retain the line
+// it was matched in.
+func copyString(s string) string {
+	return string([]byte(s))
+}
+
+func (in stringInterner) intern(s string) string {
+	if v, ok := in[s]; ok {
+		return v
+	}
+	v := copyString(s)
+	in[v] = v
+	return v
+}
+
+// Replace the strings in a parsed value with interned ones
+func (in stringInterner) internValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return in.intern(v)
+	case []string:
+		for i, s := range v {
+			v[i] = in.intern(s)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = in.internValue(item)
+		}
+	case []Parsed:
+		for _, p := range v {
+			in.internParsed(p)
+		}
+	case Parsed:
+		in.internParsed(v)
+	}
+	return value
+}
+
+func (in stringInterner) internParsed(p Parsed) {
+	for key, value := range p {
+		if network, ok := value.(string); ok && key == "network" {
+			p[key] = copyString(network) // unique for most routes
+			continue
+		}
+		p[key] = in.internValue(value)
+	}
+}
+
+// Intern the strings of the routes
+func (in stringInterner) internRoutes(routes []Parsed) {
+	if !internStrings {
+		return
+	}
+	for _, route := range routes {
+		in.internParsed(route)
+	}
+}
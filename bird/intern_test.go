@@ -0,0 +1,82 @@
+package bird
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestInternRoutes(t *testing.T) {
+	routes := []Parsed{
+		{
+			"network":       "10.0.0.0/8",
+			"from_protocol": "R1",
+			"bgp":           Parsed{"as_path": []string{"1764", "174"}},
+		},
+		{
+			"network":       "10.0.0.0/16",
+			"from_protocol": "R1",
+			"bgp":           Parsed{"as_path": []string{"1764"}},
+		},
+	}
+
+	in := stringInterner{}
+	in.internRoutes(routes)
+
+	if len(in) != 3 {
+		t.Error("Expected 3 interned strings, got:", len(in))
+	}
+	if routes[0]["network"] != "10.0.0.0/8" || routes[1]["from_protocol"] != "R1" {
+		t.Error("Interning changed the routes:", routes)
+	}
+}
+
+// Generate the birdc output of a table with similar routes
+// learned from a few neighbors
+func generateRoutesOutput(count int) string {
+	out := strings.Builder{}
+	out.WriteString("BIRD 2.0.7 ready.\n")
+	for i := 0; i < count; i++ {
+		peer := i % 4
+		fmt.Fprintf(&out, "%d.%d.%d.0/24 unicast [R%d_AS6450%d 2021-03-30 01:58:08] * (100) [AS6450%di]\n",
+			10+i/65536, (i/256)%256, i%256, peer, peer, peer)
+		fmt.Fprintf(&out, "\tvia 192.0.2.%d on eth0\n", peer)
+		out.WriteString("\tType: BGP univ\n")
+		out.WriteString("\tBGP.origin: IGP\n")
+		fmt.Fprintf(&out, "\tBGP.as_path: 6450%d 174 3356\n", peer)
+		fmt.Fprintf(&out, "\tBGP.next_hop: 192.0.2.%d\n", peer)
+		out.WriteString("\tBGP.local_pref: 100\n")
+		out.WriteString("\tBGP.ext_community: (ro, 21414, 52001) (ro, 21414, 52004)\n")
+	}
+	return out.String()
+}
+
+// Measure the heap retained by the parsed routes,
+// with and without interned strings.
+func BenchmarkParseRoutesInterned(b *testing.B) {
+	output := generateRoutesOutput(50000)
+
+	for _, interned := range []bool{false, true} {
+		b.Run(fmt.Sprintf("interned=%v", interned), func(b *testing.B) {
+			internStrings = interned
+			defer func() { internStrings = true }()
+
+			var stats runtime.MemStats
+			retained := uint64(0)
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+				runtime.ReadMemStats(&stats)
+				before := stats.HeapAlloc
+
+				parsed := parseRoutes(strings.NewReader(output))
+
+				runtime.GC()
+				runtime.ReadMemStats(&stats)
+				retained += stats.HeapAlloc - before
+				runtime.KeepAlive(parsed)
+			}
+			b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package bird
 
 import (
+	"compress/gzip"
+	"encoding/json"
 	"errors"
+	"os"
 	"sync"
 	"time"
 )
@@ -13,7 +16,9 @@ type MemoryCache struct {
 	m map[string]Parsed    // Cached data
 	a map[string]time.Time // Access times
 
-	maxKeys int // Maximum number of keys to cache
+	maxKeys   int           // Maximum number of keys to cache
+	staleTime time.Duration // Retention of expired keys
+	jitter    float64       // Random spread of the TTL
 }
 
 // NewMemoryCache creates a new MemoryCache with a maximum number of keys.
@@ -28,6 +33,14 @@ func NewMemoryCache(maxKeys int) *MemoryCache {
 	return cache
 }
 
+// Len gets the number of cached keys, including
+// expired keys which are retained as stale.
+func (c *MemoryCache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+	return len(c.m)
+}
+
 // Get a key from the cache.
 func (c *MemoryCache) Get(key string) (Parsed, error) {
 	c.Lock()
@@ -52,6 +65,28 @@ func (c *MemoryCache) Get(key string) (Parsed, error) {
 	return val, nil // cache hit
 }
 
+// GetStale gets a key from the cache, even if the TTL
+// is expired, as long as it is retained.
+func (c *MemoryCache) GetStale(key string) (Parsed, error) {
+	c.Lock()
+	val, ok := c.m[key]
+	c.Unlock()
+
+	if !ok {
+		return NilParse, errors.New("Failed to retrive key '" + key + "' from MemoryCache.")
+	}
+
+	ttl, ok := val["ttl"].(time.Time)
+	if !ok {
+		return NilParse, errors.New("Invalid TTL value for key '" + key + "'")
+	}
+	if ttl.Add(c.staleTime).Before(time.Now()) {
+		return NilParse, errors.New("Stale entry expired for key '" + key + "'")
+	}
+
+	return val, nil
+}
+
 // Set a key in the cache.
 func (c *MemoryCache) Set(key string, val Parsed, ttl int) error {
 	c.Lock()
@@ -73,7 +108,7 @@ func (c *MemoryCache) Set(key string, val Parsed, ttl int) error {
 	}
 
 	cachedAt := time.Now().UTC()
-	cacheTTL := cachedAt.Add(time.Duration(ttl) * time.Minute)
+	cacheTTL := cachedAt.Add(jitterTtl(time.Duration(ttl)*time.Minute, c.jitter))
 
 	// This is not a really ... clean way of doing this.
 	val["ttl"] = cacheTTL
@@ -105,12 +140,12 @@ func (c *MemoryCache) expireLRU() {
 }
 
 // Expire all keys in cache that are older than the
-// TTL value.
+// TTL value. Expired keys are retained for the stale time.
 func (c *MemoryCache) Expire() int {
 	c.Lock()
 	defer c.Unlock()
 
-	now := time.Now().UTC()
+	now := time.Now().UTC().Add(-c.staleTime)
 
 	expiredKeys := []string{}
 	for key := range c.m {
@@ -127,3 +162,128 @@ func (c *MemoryCache) Expire() int {
 
 	return len(expiredKeys)
 }
+
+// A cache entry in a snapshot on disk
+type memoryCacheEntry struct {
+	Value    Parsed    `json:"value"`
+	Accessed time.Time `json:"accessed"`
+}
+
+// Save writes a gzipped JSON snapshot of all entries
+// in the cache to a file. The file is replaced atomically.
+func (c *MemoryCache) Save(filename string) error {
+	c.Lock()
+	entries := make(map[string]memoryCacheEntry, len(c.m))
+	for key, val := range c.m {
+		entries[key] = memoryCacheEntry{Value: val, Accessed: c.a[key]}
+	}
+	payload, err := json.Marshal(entries)
+	c.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp := filename + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write(payload); err != nil {
+		f.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filename)
+}
+
+// Load restores the entries of a snapshot written by Save.
+// Expired entries are skipped, unless they are retained. The number of restored
+// entries is returned.
+func (c *MemoryCache) Load(filename string) (int, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+
+	entries := map[string]memoryCacheEntry{}
+	if err := json.NewDecoder(gz).Decode(&entries); err != nil {
+		return 0, err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	now := time.Now().UTC()
+	count := 0
+	for key, entry := range entries {
+		val, ok := restoreValue(map[string]interface{}(entry.Value)).(Parsed)
+		if !ok {
+			continue
+		}
+		ttl, err := parseCacheTTL(val["ttl"])
+		if err != nil || ttl.Add(c.staleTime).Before(now) {
+			continue
+		}
+		cachedAt, err := parseCacheTTL(val["cached_at"])
+		if err != nil {
+			continue
+		}
+		val["ttl"] = ttl
+		val["cached_at"] = cachedAt
+
+		if _, ok := c.a[key]; !ok && len(c.a) >= c.maxKeys {
+			c.expireLRU()
+		}
+		c.m[key] = val
+		c.a[key] = entry.Accessed
+		count++
+	}
+
+	return count, nil
+}
+
+// Values decoded from JSON are plain maps and slices.
+// Restore maps and lists of maps as Parsed, like they
+// are returned by the parsers.
+func restoreValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		res := Parsed{}
+		for key, item := range v {
+			res[key] = restoreValue(item)
+		}
+		return res
+	case []interface{}:
+		list := make([]Parsed, 0, len(v))
+		for _, item := range v {
+			p, ok := restoreValue(item).(Parsed)
+			if !ok {
+				break
+			}
+			list = append(list, p)
+		}
+		if len(list) > 0 && len(list) == len(v) {
+			return list
+		}
+		for i, item := range v {
+			v[i] = restoreValue(item)
+		}
+		return v
+	}
+	return value
+}
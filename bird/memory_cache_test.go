@@ -1,7 +1,12 @@
 package bird
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func TestMemoryCacheAccess(t *testing.T) {
@@ -136,3 +141,110 @@ func TestMemoryCacheNoCache(t *testing.T) {
 		t.Error("Expected error, got nil")
 	}
 }
+
+func TestMemoryCacheSaveLoad(t *testing.T) {
+	f, err := openFile("routes_bird2_ipv4.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dir, err := ioutil.TempDir("", "birdwatcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cache.json.gz")
+
+	cache := NewMemoryCache(100)
+	if err := cache.Set("routes", parseRoutes(f), 5); err != nil {
+		t.Error(err)
+	}
+	if err := cache.Set("expired", Parsed{"foo": 23}, 5); err != nil {
+		t.Error(err)
+	}
+	cache.m["expired"]["ttl"] = time.Now().Add(-time.Minute)
+
+	if err := cache.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewMemoryCache(100)
+	count, err := restored.Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Error("Expected 1 restored entry, got:", count)
+	}
+
+	parsed, err := restored.Get("routes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes, ok := parsed["routes"].([]Parsed)
+	if !ok || len(routes) == 0 {
+		t.Fatal("Expected routes to be restored, got:", parsed["routes"])
+	}
+	if _, ok := routes[0]["bgp"].(Parsed); !ok {
+		t.Error("Expected bgp attributes to be restored as Parsed")
+	}
+	if _, ok := parsed["cached_at"].(time.Time); !ok {
+		t.Error("Expected cached_at to be restored as time")
+	}
+
+	if _, err := restored.Get("expired"); err == nil {
+		t.Error("Expected expired entry not to be restored")
+	}
+}
+
+func TestMemoryCacheStale(t *testing.T) {
+	cache := NewMemoryCache(100)
+	cache.staleTime = 10 * time.Minute
+
+	if err := cache.Set("recent", Parsed{"foo": 23}, 5); err != nil {
+		t.Error(err)
+	}
+	if err := cache.Set("old", Parsed{"foo": 42}, 5); err != nil {
+		t.Error(err)
+	}
+	cache.m["recent"]["ttl"] = time.Now().Add(-5 * time.Minute)
+	cache.m["old"]["ttl"] = time.Now().Add(-15 * time.Minute)
+
+	if _, err := cache.Get("recent"); err == nil {
+		t.Error("Expected expired entry not to be returned by Get")
+	}
+	if val, err := cache.GetStale("recent"); err != nil || val["foo"] != 23 {
+		t.Error("Expected stale entry, got:", val, err)
+	}
+
+	if count := cache.Expire(); count != 1 {
+		t.Error("Expected only the old entry to be expired, got:", count)
+	}
+	if _, err := cache.GetStale("old"); err == nil {
+		t.Error("Expected old entry not to be retained")
+	}
+}
+
+func TestMemoryCacheTtlJitter(t *testing.T) {
+	cache := NewMemoryCache(100)
+	cache.jitter = 0.1
+
+	min := time.Now().Add(54 * time.Minute)
+	max := time.Now().Add(66 * time.Minute)
+	expiries := map[time.Time]bool{}
+	for i := 0; i < 10; i++ {
+		key := strconv.Itoa(i)
+		if err := cache.Set(key, Parsed{}, 60); err != nil {
+			t.Fatal(err)
+		}
+		ttl := cache.m[key]["ttl"].(time.Time)
+		if ttl.Before(min) || ttl.After(max) {
+			t.Error("Expected TTL within the jitter, got:", ttl)
+		}
+		expiries[ttl] = true
+	}
+	if len(expiries) < 2 {
+		t.Error("Expected entries to expire at different times")
+	}
+}
@@ -2,33 +2,45 @@ package bird
 
 import (
 	"bufio"
+	"hash/fnv"
 	"io"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // WorkerPoolSize is the number of go routines used to parse routing tables concurrently
 var WorkerPoolSize = 8
 
+// Number of workers currently parsing a block of routes
+var workersBusy int64
+
 var (
 	ParserConf ParserConfig
 	regex      struct {
 		status struct {
 			startLine     *regexp.Regexp
 			routerID      *regexp.Regexp
+			hostname      *regexp.Regexp
 			currentServer *regexp.Regexp
 			lastReboot    *regexp.Regexp
 			lastReconfig  *regexp.Regexp
 		}
 		protocol struct {
 			channel      *regexp.Regexp
+			channelName  *regexp.Regexp
 			protocol     *regexp.Regexp
 			numericValue *regexp.Regexp
 			routes       *regexp.Regexp
 			stringValue  *regexp.Regexp
 			routeChanges *regexp.Regexp
+			bgpRole      *regexp.Regexp
+			limit        *regexp.Regexp
+			limitAction  *regexp.Regexp
 			short        *regexp.Regexp
 		}
 		symbols struct {
@@ -37,6 +49,23 @@ var (
 		routeCount struct {
 			countRx *regexp.Regexp
 		}
+		memory struct {
+			usage *regexp.Regexp
+		}
+		ospf struct {
+			scope *regexp.Regexp
+			lsa   *regexp.Regexp
+		}
+		rip struct {
+			protocol *regexp.Regexp
+			iface    *regexp.Regexp
+			neighbor *regexp.Regexp
+		}
+		iface struct {
+			start   *regexp.Regexp
+			flags   *regexp.Regexp
+			address *regexp.Regexp
+		}
 		routes struct {
 			startDefinition   *regexp.Regexp
 			second            *regexp.Regexp
@@ -47,8 +76,15 @@ var (
 			extendedCommunity *regexp.Regexp
 			origin            *regexp.Regexp
 			prefix            *regexp.Regexp
+			distinguisher     *regexp.Regexp
 			gateway           *regexp.Regexp
 			iface             *regexp.Regexp
+			table             *regexp.Regexp
+			preference        *regexp.Regexp
+			preferenceAttr    *regexp.Regexp
+			igpMetric         *regexp.Regexp
+			ospf              *regexp.Regexp
+			ignored           *regexp.Regexp
 		}
 	}
 )
@@ -62,20 +98,38 @@ func init() {
 
 	regex.status.startLine = regexp.MustCompile(`^BIRD\s(.+)\s*$`)
 	regex.status.routerID = regexp.MustCompile(`^Router\sID\sis\s([0-9\.]+)\s*$`)
+	regex.status.hostname = regexp.MustCompile(`^Hostname\sis\s(\S+)\s*$`)
 	regex.status.currentServer = regexp.MustCompile(`^Current\sserver\stime\sis\s([0-9\-]+\s[0-9\:\.]+)\s*$`)
 	regex.status.lastReboot = regexp.MustCompile(`^Last\sreboot\son\s([0-9\-]+\s[0-9\:\.]+)\s*$`)
 	regex.status.lastReconfig = regexp.MustCompile(`^Last\sreconfiguration\son\s([0-9\-]+\s[0-9\:\.]+)\s*$`)
 
 	regex.symbols.keyRx = regexp.MustCompile(`^([^\s]+)\s+(.+)\s*$`)
 
-	regex.routeCount.countRx = regexp.MustCompile(`^(\d+)\s+of\s+(\d+)\s+routes.*$`)
+	regex.routeCount.countRx = regexp.MustCompile(`^(\d+)\s+of\s+(\d+)\s+routes(?:\s+for\s+(\d+)\s+networks)?.*$`)
+
+	regex.memory.usage = regexp.MustCompile(`^([^:]+):\s+([\d\.]+)\s*(B|kB|MB|GB)(?:\s+([\d\.]+)\s*(B|kB|MB|GB))?\s*$`)
+
+	regex.ospf.scope = regexp.MustCompile(`^(Global|Area\s+(\S+)|Link\s+(\S+))\s*$`)
+	regex.ospf.lsa = regexp.MustCompile(`^\s*([0-9a-f]{4})\s+(\S+)\s+(\S+)\s+([0-9a-f]{8})\s+(\d+)\s+([0-9a-f]{4})\s*$`)
+
+	regex.rip.protocol = regexp.MustCompile(`^(\S+):\s*$`)
+	regex.rip.iface = regexp.MustCompile(`^(` + re_ifname + `)\s+(Up|Down)\s+(\d+)\s+(\d+)\s+([\d\.]+)\s*$`)
+	regex.rip.neighbor = regexp.MustCompile(`^(` + re_ip + `)\s+(` + re_ifname + `)\s+(\d+)\s+(\d+)\s+([\d\.]+)\s*$`)
+
+	regex.iface.start = regexp.MustCompile(`^(\S+)\s+(?i:(up|down))\s+\(index=(\d+)(?:\s+master=(\S+))?\)\s*$`)
+	regex.iface.flags = regexp.MustCompile(`^\s+(.*?)\s*MTU=(\d+)\s*$`)
+	regex.iface.address = regexp.MustCompile(`^\s+(` + re_prefix + `)\s+\(([^\)]*)\)\s*$`)
 
 	regex.protocol.channel = regexp.MustCompile("Channel ipv([46])")
+	regex.protocol.channelName = regexp.MustCompile(`^(\s+)Channel\s+(\S+)\s*$`)
 	// regex.protocol.protocol = regexp.MustCompile(`^(?:1002\-)?([^\s]+)\s+(BGP|RPKI|Pipe|BFD|Direct|Device|Kernel)\s+([^\s]+)\s+([^\s]+)\s+(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}|[^\s]+)(?:\s+(.*?)\s*)?$`)
 	regex.protocol.protocol = regexp.MustCompile(`^(?:1002\-)?([^\s]+)\s+(\w+)\s+([^\s]+)\s+([^\s]+)\s+(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}|[^\s]+)(?:\s+(.*?)\s*)?$`)
 	regex.protocol.numericValue = regexp.MustCompile(`^\s+([^:]+):\s+([\d]+)\s*$`)
 	regex.protocol.routes = regexp.MustCompile(`^\s+Routes:\s+(.*)`)
 	regex.protocol.stringValue = regexp.MustCompile(`^\s+([^:]+):\s+(.+)\s*$`)
+	regex.protocol.bgpRole = regexp.MustCompile(`^\s+Role:\s+(\S+)\s*$`)
+	regex.protocol.limit = regexp.MustCompile(`^\s+(Receive|Import|Export|Route) limit:\s+(\d+)(?:\s+\[HIT\])?\s*$`)
+	regex.protocol.limitAction = regexp.MustCompile(`^\s+Action:\s+(\S+)\s*$`)
 	regex.protocol.routeChanges = regexp.MustCompile(`(Import|Export) (updates|withdraws):\s+(\d+|---)\s+(\d+|---)\s+(\d+|---)\s+(\d+|---)\s+(\d+|---)\s*$`)
 
 	regex.routes.startDefinition = regexp.MustCompile(`^(` + re_prefix + `)\s+via\s+(` + re_ip + `)\s+on\s+(` + re_ifname + `)\s+\[([\w\.:]+)\s+([0-9\-\:\s]+)(?:\s+from\s+(` + re_prefix + `)){0,1}\]\s+(?:(\*)\s+){0,1}\((\d+)(?:\/\d+){0,1}|\?\).*`)
@@ -87,9 +141,17 @@ func init() {
 	regex.routes.largeCommunity = regexp.MustCompile(`^\((\d+),\s*(\d+),\s*(\d+)\)`)
 	regex.routes.extendedCommunity = regexp.MustCompile(`^\(([^,]+),\s*([^,]+),\s*([^,]+)\)`)
 	regex.routes.origin = regexp.MustCompile(`\([^\(]*\)\s*`)
-	regex.routes.prefix = regexp.MustCompile(`^(` + re_prefix + `)?\s+(?:unicast|blackhole)\s+\[([\w\.:]+)\s+([0-9\-\:\.\s]+)(?:\s+from\s+(` + re_prefix + `))?\]\s+(?:(\*)\s+)?\((\d+)(?:\/\d+)?(?:\/[^\)]*)?\).*$`)
-	regex.routes.gateway = regexp.MustCompile(`^\s+via\s+(` + re_ip + `)\s+on\s+(` + re_ifname + `)\s*$`)
+	regex.routes.prefix = regexp.MustCompile(`^(` + re_prefix + `)?\s+(?:unicast|blackhole|multipath)\s+\[([\w\.:]+)\s+([0-9\-\:\.\s]+)(?:\s+from\s+(` + re_prefix + `))?\]\s+(?:(\*)\s+)?(?:(?:I|IA|E1|E2)\s+)?\((\d+)(?:\/\d+)?(?:\/[^\)]*)?\).*$`)
+	regex.routes.distinguisher = regexp.MustCompile(`^((?:` + re_ip + `|\d+):\d+)\s+(` + re_prefix + `\s+.*)$`)
+	regex.routes.gateway = regexp.MustCompile(`^\s+via\s+(` + re_ip + `)\s+on\s+(` + re_ifname + `)(?:\s+mpls\s+([\d\/]+))?(?:\s+onlink)?(?:\s+weight\s+(\d+))?\s*$`)
 	regex.routes.iface = regexp.MustCompile(`^\s+dev\s+(` + re_ifname + `)\s*$`)
+	regex.routes.table = regexp.MustCompile(`^Table\s+(\S+):\s*$`)
+	regex.routes.preference = regexp.MustCompile(`\((\d+)(?:/(\d+|\?))?(?:/(\d+|\?))?\)`)
+	regex.routes.preferenceAttr = regexp.MustCompile(`^\s+(?i:preference):\s+(\d+)\s*$`)
+	regex.routes.igpMetric = regexp.MustCompile(`^\s+igp_metric:\s+(\d+)\s*$`)
+	// Known attributes, which are not part of the result
+	regex.routes.ignored = regexp.MustCompile(`^\s+(?:from|Internal route handling values|Kernel\.\w+|krt_\w+):`)
+	regex.routes.ospf = regexp.MustCompile(`^\s+(?:(?i)ospf)\.(\w+):\s+(.+?)\s*$`)
 }
 
 func dirtyContains(l []string, e string) bool {
@@ -121,6 +183,8 @@ func parseStatus(reader io.Reader) Parsed {
 			res["version"] = regex.status.startLine.FindStringSubmatch(line)[1]
 		} else if regex.status.routerID.MatchString(line) {
 			res["router_id"] = regex.status.routerID.FindStringSubmatch(line)[1]
+		} else if regex.status.hostname.MatchString(line) {
+			res["hostname"] = regex.status.hostname.FindStringSubmatch(line)[1]
 		} else if regex.status.currentServer.MatchString(line) {
 			res["current_server"] = regex.status.currentServer.FindStringSubmatch(line)[1]
 		} else if regex.status.lastReboot.MatchString(line) {
@@ -170,8 +234,99 @@ func parseProtocolsShort(reader io.Reader) Parsed {
 	return Parsed{"protocols": res}
 }
 
+// Get the protocols of the terse protocols list,
+// which are decoded as maps from redis.
+func shortProtocols(protocols Parsed) map[string]Parsed {
+	res := map[string]Parsed{}
+	var list map[string]interface{}
+	switch p := protocols["protocols"].(type) {
+	case Parsed:
+		list = p
+	case map[string]interface{}: // decoded from redis
+		list = p
+	}
+	for name, p := range list {
+		switch protocol := p.(type) {
+		case Parsed:
+			res[name] = protocol
+		case map[string]interface{}:
+			res[name] = Parsed(protocol)
+		}
+	}
+	return res
+}
+
+// Keep only the type and state of the protocols
+// of the terse protocols list for frequent polling.
+func protocolsStates(protocols Parsed) Parsed {
+	res := Parsed{}
+	for name, protocol := range shortProtocols(protocols) {
+		res[name] = Parsed{
+			"type":  protocol["proto"],
+			"state": protocol["state"],
+		}
+	}
+
+	return Parsed{"protocols": res}
+}
+
+// BGP sessions are summarized in these buckets
+var bgpSummaryBuckets = []string{"up", "down", "connect", "active", "idle"}
+
+// Get the bucket of a BGP session by the state of the
+// protocol and the BGP state in the info column.
+func bgpSessionBucket(state string, info string) string {
+	switch state {
+	case "up":
+		return "up"
+	case "down":
+		return "down"
+	}
+
+	fields := strings.Fields(info)
+	if len(fields) == 0 {
+		return "idle"
+	}
+	switch strings.ToLower(fields[0]) {
+	case "established":
+		return "up"
+	case "connect", "opensent", "openconfirm":
+		return "connect"
+	case "active":
+		return "active"
+	}
+	return "idle"
+}
+
+// Group the BGP sessions of the terse protocols list
+// by their state, with the count and sorted names.
+func bgpSummary(protocols Parsed) Parsed {
+	names := map[string][]string{}
+	for name, protocol := range shortProtocols(protocols) {
+		if protocol["proto"] != "BGP" {
+			continue
+		}
+		state, _ := protocol["state"].(string)
+		info, _ := protocol["info"].(string)
+		bucket := bgpSessionBucket(state, info)
+		names[bucket] = append(names[bucket], name)
+	}
+
+	summary := Parsed{}
+	for _, bucket := range bgpSummaryBuckets {
+		sort.Strings(names[bucket])
+		summary[bucket] = Parsed{
+			"count":     len(names[bucket]),
+			"protocols": append([]string{}, names[bucket]...),
+		}
+	}
+	return Parsed{"summary": summary}
+}
+
 func parseProtocols(reader io.Reader) Parsed {
 	res := Parsed{}
+	errors := []Parsed{}
+	failed := 0
 
 	proto := ""
 
@@ -183,7 +338,18 @@ func parseProtocols(reader io.Reader) Parsed {
 			if !emptyString(proto) {
 				parsed := parseProtocol(proto)
 
-				res[parsed["protocol"].(string)] = parsed
+				// Protocols without a parsable header are skipped
+				if name, ok := parsed["protocol"].(string); ok {
+					res[name] = parsed
+				} else {
+					failed++
+					if ParserConf.ReportParseErrors {
+						errors = append(errors, Parsed{
+							"line":  strings.SplitN(proto, "\n", 2)[0],
+							"error": "unrecognized protocol",
+						})
+					}
+				}
 			}
 			proto = ""
 		} else {
@@ -191,7 +357,14 @@ func parseProtocols(reader io.Reader) Parsed {
 		}
 	}
 
-	return Parsed{"protocols": res}
+	parsed := Parsed{"protocols": res}
+	if len(errors) > 0 {
+		parsed[ParseErrorsKey] = errors
+	}
+	if failed > 0 {
+		parsed[parseErrorCountKey] = failed
+	}
+	return parsed
 }
 
 func parseSymbols(reader io.Reader) Parsed {
@@ -222,13 +395,24 @@ func parseSymbols(reader io.Reader) Parsed {
 type blockJob struct {
 	lines    []string
 	position int
+	table    string
 }
 
 type blockParsed struct {
 	items    []Parsed
+	errors   []Parsed
+	failed   int
 	position int
 }
 
+// ParseErrorsKey holds the lines which could not be parsed,
+// if parse errors are reported.
+const ParseErrorsKey = "_parse_errors"
+
+// The number of lines which could not be parsed, which is
+// counted in the metrics and removed before caching.
+const parseErrorCountKey = "_parse_error_count"
+
 func parseRoutes(reader io.Reader) Parsed {
 	jobs := make(chan blockJob)
 	out := startRouteWorkers(jobs)
@@ -238,22 +422,30 @@ func parseRoutes(reader io.Reader) Parsed {
 
 	pos := 0
 	block := []string{}
+	table := ""
 	lines := newLineIterator(reader, true)
 
 	for lines.next() {
 		line := lines.string()
 
 		if line[0] != 32 && line[0] != 9 && len(block) > 0 {
-			jobs <- blockJob{block, pos}
+			jobs <- blockJob{block, pos, table}
 			pos++
 			block = []string{}
 		}
 
+		// The routes following a table header are
+		// from this table, until the next header.
+		if groups := regex.routes.table.FindStringSubmatch(line); groups != nil {
+			table = groups[1]
+			continue
+		}
+
 		block = append(block, line)
 	}
 
 	if len(block) > 0 {
-		jobs <- blockJob{block, pos}
+		jobs <- blockJob{block, pos, table}
 	}
 
 	close(jobs)
@@ -282,12 +474,26 @@ func startRouteConsumer(out <-chan blockParsed) chan Parsed {
 
 	go func() {
 		byBlock := map[int][]Parsed{}
+		errorsByBlock := map[int][]Parsed{}
 		count := 0
+		failed := 0
 		for r := range out {
 			count++
 			byBlock[r.position] = r.items
+			if len(r.errors) > 0 {
+				errorsByBlock[r.position] = r.errors
+			}
+			failed += r.failed
+		}
+
+		parsed := Parsed{"routes": sortedSliceForRouteBlocks(byBlock, count)}
+		if len(errorsByBlock) > 0 {
+			parsed[ParseErrorsKey] = sortedSliceForRouteBlocks(errorsByBlock, count)
 		}
-		res <- Parsed{"routes": sortedSliceForRouteBlocks(byBlock, count)}
+		if failed > 0 {
+			parsed[parseErrorCountKey] = failed
+		}
+		res <- parsed
 	}()
 
 	return res
@@ -309,15 +515,20 @@ func sortedSliceForRouteBlocks(byBlock map[int][]Parsed, numBlocks int) []Parsed
 }
 
 func workerForRouteBlockParsing(jobs <-chan blockJob, out chan<- blockParsed, wg *sync.WaitGroup) {
+	interner := stringInterner{}
 	for j := range jobs {
-		parseRouteLines(j.lines, j.position, out)
+		atomic.AddInt64(&workersBusy, 1)
+		parseRouteLines(j.lines, j.position, j.table, interner, out)
+		atomic.AddInt64(&workersBusy, -1)
 	}
 	wg.Done()
 }
 
-func parseRouteLines(lines []string, position int, ch chan<- blockParsed) {
+func parseRouteLines(lines []string, position int, table string, interner stringInterner, ch chan<- blockParsed) {
 	route := Parsed{}
 	routes := []Parsed{}
+	errors := []Parsed{}
+	failed := 0
 
 	for i := 0; i < len(lines); {
 		line := lines[i]
@@ -327,16 +538,32 @@ func parseRouteLines(lines []string, position int, ch chan<- blockParsed) {
 			continue
 		}
 
+		// VPN routes are prefixed with a route distinguisher
+		distinguisher := ""
+		if groups := regex.routes.distinguisher.FindStringSubmatch(line); groups != nil {
+			distinguisher = groups[1]
+			line = groups[2]
+		}
+
 		if regex.routes.prefix.MatchString(line) {
+			groups := regex.routes.prefix.FindStringSubmatch(line)
 			formerPrefix := ""
 			if len(route) > 0 {
 				routes = append(routes, route)
 
 				formerPrefix = route["network"].(string)
+				// Further paths of a VPN route share its distinguisher
+				if rd, ok := route["route_distinguisher"].(string); ok && groups[1] == "" {
+					distinguisher = rd
+				}
 				route = Parsed{}
 			}
 
-			parseMainRouteDetailBird2(regex.routes.prefix.FindStringSubmatch(line), route, formerPrefix)
+			parseMainRouteDetailBird2(groups, route, formerPrefix)
+			parseRoutePreference(line, route)
+			if distinguisher != "" {
+				route["route_distinguisher"] = distinguisher
+			}
 		} else if regex.routes.startDefinition.MatchString(line) {
 			if len(route) > 0 {
 				routes = append(routes, route)
@@ -344,12 +571,27 @@ func parseRouteLines(lines []string, position int, ch chan<- blockParsed) {
 			}
 
 			parseMainRouteDetail(regex.routes.startDefinition.FindStringSubmatch(line), route)
+			parseRoutePreference(line, route)
 		} else if regex.routes.gateway.MatchString(line) {
 			parseRoutesGatewayBird2(regex.routes.gateway.FindStringSubmatch(line), route)
+		} else if regex.routes.iface.MatchString(line) {
+			// Device routes have an interface, but no gateway
+			route["interface"] = regex.routes.iface.FindStringSubmatch(line)[1]
 		} else if regex.routes.second.MatchString(line) {
 			routes = append(routes, route)
 
 			route = parseRoutesSecond(line, route)
+			parseRoutePreference(line, route)
+		} else if regex.routes.preferenceAttr.MatchString(line) {
+			if !dirtyContains(ParserConf.FilterFields, "preference") {
+				route["preference"] = parseInt(regex.routes.preferenceAttr.FindStringSubmatch(line)[1])
+			}
+		} else if regex.routes.igpMetric.MatchString(line) {
+			if !dirtyContains(ParserConf.FilterFields, "igp_metric") {
+				route["igp_metric"] = parseInt(regex.routes.igpMetric.FindStringSubmatch(line)[1])
+			}
+		} else if regex.routes.ospf.MatchString(line) {
+			parseRoutesOspf(regex.routes.ospf.FindStringSubmatch(line), route)
 		} else if regex.routes.routeType.MatchString(line) {
 			submatch := regex.routes.routeType.FindStringSubmatch(line)[1]
 			route["type"] = strings.Split(submatch, " ")
@@ -388,6 +630,17 @@ func parseRouteLines(lines []string, position int, ch chan<- blockParsed) {
 
 			parseRoutesBgp(line, bgp)
 			route["bgp"] = bgp
+			setRouteBgpNextHop(route, bgp)
+		} else if regex.routes.ignored.MatchString(line) {
+			// Not a parse error
+		} else {
+			failed++
+			if ParserConf.ReportParseErrors {
+				errors = append(errors, Parsed{
+					"line":  lines[i],
+					"error": "unrecognized route line",
+				})
+			}
 		}
 
 		i++
@@ -397,7 +650,72 @@ func parseRouteLines(lines []string, position int, ch chan<- blockParsed) {
 		routes = append(routes, route)
 	}
 
-	ch <- blockParsed{routes, position}
+	if table != "" && !dirtyContains(ParserConf.FilterFields, "table") {
+		for _, route := range routes {
+			route["table"] = table
+		}
+	}
+
+	if !dirtyContains(ParserConf.FilterFields, "id") {
+		for _, route := range routes {
+			route["id"] = routeID(route)
+		}
+	}
+
+	interner.internRoutes(routes)
+	ch <- blockParsed{routes, errors, failed, position}
+}
+
+// Mask of the route IDs, which are exact
+// as numbers in JavaScript and JSON
+const routeIDMask = 1<<53 - 1
+
+// routeID hashes the network, AS path, gateway and protocol
+// of a route. The ID identifies the route across responses.
+func routeID(route Parsed) int64 {
+	h := fnv.New64a()
+	for _, key := range []string{"network", "gateway", "from_protocol"} {
+		value, _ := route[key].(string)
+		h.Write([]byte(value))
+		h.Write([]byte{0})
+	}
+	if bgp, ok := route["bgp"].(Parsed); ok {
+		path, _ := bgp["as_path"].([]string)
+		h.Write([]byte(strings.Join(path, " ")))
+	}
+	return int64(h.Sum64() & routeIDMask)
+}
+
+// Parse the preference and the metric of the route from
+// the first line, like (150/20) for OSPF or (100/10) for
+// BGP with a recursive next hop. Unknown metrics are
+// shown as ? and omitted.
+func parseRoutePreference(line string, route Parsed) {
+	groups := regex.routes.preference.FindStringSubmatch(line)
+	if groups == nil {
+		return
+	}
+	if !dirtyContains(ParserConf.FilterFields, "preference") {
+		route["preference"] = parseInt(groups[1])
+	}
+	if groups[2] != "" && groups[2] != "?" && !dirtyContains(ParserConf.FilterFields, "igp_metric") {
+		route["igp_metric"] = parseInt(groups[2])
+	}
+}
+
+// Parse an OSPF attribute of a route, like
+// OSPF.metric1: 20. Numeric values are integers.
+func parseRoutesOspf(groups []string, route Parsed) {
+	ospf, ok := route["ospf"].(Parsed)
+	if !ok {
+		ospf = Parsed{}
+		route["ospf"] = ospf
+	}
+	if _, err := strconv.ParseInt(groups[2], 10, 64); err == nil {
+		ospf[groups[1]] = parseInt(groups[2])
+	} else {
+		ospf[groups[1]] = groups[2]
+	}
 }
 
 func parseMainRouteDetail(groups []string, route Parsed) {
@@ -409,6 +727,11 @@ func parseMainRouteDetail(groups []string, route Parsed) {
 	route["learnt_from"] = groups[6]
 	route["primary"] = groups[7] == "*"
 	route["metric"] = parseInt(groups[8])
+	route["next_hops"] = []Parsed{{
+		"gateway":   groups[2],
+		"interface": groups[3],
+		"weight":    int64(1),
+	}}
 
 	for k := range route {
 		if dirtyContains(ParserConf.FilterFields, k) {
@@ -437,9 +760,32 @@ func parseMainRouteDetailBird2(groups []string, route Parsed, formerPrefix strin
 	}
 }
 
+// Multipath routes have a gateway line for each next hop.
+// The gateway and interface of the route are taken from
+// the first one.
 func parseRoutesGatewayBird2(groups []string, route Parsed) {
-	route["gateway"] = groups[1]
-	route["interface"] = groups[2]
+	weight := int64(1) // only present for multipath routes
+	if len(groups[4]) > 0 {
+		weight = parseInt(groups[4])
+	}
+	nextHop := Parsed{
+		"gateway":   groups[1],
+		"interface": groups[2],
+		"weight":    weight,
+	}
+	if len(groups[3]) > 0 {
+		nextHop["mpls_labels"] = parseLabelStack(groups[3])
+	}
+
+	nextHops, ok := route["next_hops"].([]Parsed)
+	if !ok {
+		route["gateway"] = groups[1]
+		route["interface"] = groups[2]
+		if labels, ok := nextHop["mpls_labels"]; ok {
+			route["mpls_labels"] = labels
+		}
+	}
+	route["next_hops"] = append(nextHops, nextHop)
 }
 
 func parseRoutesSecond(line string, route Parsed) Parsed {
@@ -472,6 +818,8 @@ func parseRoutesBgp(line string, bgp Parsed) {
 		parseRoutesLargeCommunities(groups, bgp)
 	} else if groups[1] == "ext_community" {
 		parseRoutesExtendedCommunities(groups, bgp)
+	} else if groups[1] == "mpls_label_stack" {
+		bgp["mpls_label_stack"] = parseLabelStack(groups[2])
 	} else if groups[1] == "as_path" || groups[1] == "path" {
 		bgp["as_path"] = strings.Split(groups[2], " ")
 	} else {
@@ -479,6 +827,22 @@ func parseRoutesBgp(line string, bgp Parsed) {
 	}
 }
 
+// The BGP next hop is provided separately from the gateway,
+// which differs if the next hop is resolved recursively. An
+// IPv6 next hop may be followed by a link-local address.
+func setRouteBgpNextHop(route Parsed, bgp Parsed) {
+	nextHop, _ := bgp["next_hop"].(string)
+	addrs := strings.Fields(nextHop)
+	if len(addrs) == 0 {
+		return
+	}
+
+	route["bgp_next_hop"] = addrs[0]
+	if len(addrs) > 1 {
+		route["bgp_next_hop_link_local"] = addrs[1]
+	}
+}
+
 func parseRoutesCommunities(groups []string, res Parsed) {
 	communities := [][]int64{}
 	for _, community := range regex.routes.origin.FindAllString(groups[2], -1) {
@@ -518,6 +882,311 @@ func parseRoutesExtendedCommunities(groups []string, res Parsed) {
 	}
 
 	res["ext_communities"] = communities
+
+	// Route targets of VPN routes
+	targets := []string{}
+	for _, community := range communities {
+		values := community.([]interface{})
+		if values[0] == "rt" {
+			targets = append(targets, values[1].(string)+":"+values[2].(string))
+		}
+	}
+	if len(targets) > 0 {
+		res["route_targets"] = targets
+	}
+}
+
+// Parse a MPLS label stack like "100/200" or "100 200"
+func parseLabelStack(stack string) []int64 {
+	labels := []int64{}
+	for _, label := range strings.FieldsFunc(stack, func(c rune) bool {
+		return c == '/' || c == ' '
+	}) {
+		labels = append(labels, parseInt(label))
+	}
+	return labels
+}
+
+func parseRawOutput(reader io.Reader) Parsed {
+	output := []string{}
+
+	lines := newLineIterator(reader, false)
+	for lines.next() {
+		output = append(output, lines.string())
+	}
+
+	return Parsed{"output": output}
+}
+
+// Convert a memory size like "10.7 MB" to bytes
+func parseMemorySize(value string, unit string) int64 {
+	size, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+
+	switch unit {
+	case "kB":
+		size *= 1024
+	case "MB":
+		size *= 1024 * 1024
+	case "GB":
+		size *= 1024 * 1024 * 1024
+	}
+
+	return int64(size)
+}
+
+func parseMemory(reader io.Reader) Parsed {
+	res := Parsed{}
+
+	lines := newLineIterator(reader, true)
+	for lines.next() {
+		line := lines.string()
+
+		if specialLine(line) {
+			continue
+		}
+
+		groups := regex.memory.usage.FindStringSubmatch(line)
+		if groups == nil {
+			continue
+		}
+
+		usage := Parsed{
+			"effective": parseMemorySize(groups[2], groups[3]),
+		}
+		if len(groups[4]) > 0 { // Overhead is reported since bird2
+			usage["overhead"] = parseMemorySize(groups[4], groups[5])
+		}
+		res[treatKey(groups[1])] = usage
+	}
+
+	return Parsed{"memory": res}
+}
+
+// Parse the LSAs of the OSPF link-state database. The LSAs
+// are listed by flooding scope: Global, by area and by link.
+// Other messages, e.g. if OSPF is not running, are reported
+// as message.
+func parseOspfLsadb(reader io.Reader) Parsed {
+	lsadb := []Parsed{}
+	res := Parsed{}
+
+	scope := Parsed{}
+
+	lines := newLineIterator(reader, true)
+	for lines.next() {
+		line := lines.string()
+
+		if specialLine(line) || strings.HasPrefix(strings.TrimSpace(line), "Type ") {
+			continue
+		}
+
+		if groups := regex.ospf.scope.FindStringSubmatch(line); groups != nil {
+			switch {
+			case groups[2] != "":
+				scope = Parsed{"scope": "area", "area": groups[2]}
+			case groups[3] != "":
+				scope = Parsed{"scope": "link", "interface": groups[3]}
+			default:
+				scope = Parsed{"scope": "global"}
+			}
+			continue
+		}
+
+		groups := regex.ospf.lsa.FindStringSubmatch(line)
+		if groups == nil {
+			res["message"] = strings.TrimSpace(line)
+			continue
+		}
+
+		lsa := Parsed{
+			"type":     groups[1],
+			"ls_id":    groups[2],
+			"router":   groups[3],
+			"sequence": groups[4],
+			"age":      parseInt(groups[5]),
+			"checksum": groups[6],
+		}
+		for k, v := range scope {
+			lsa[k] = v
+		}
+		lsadb = append(lsadb, lsa)
+	}
+
+	res["lsadb"] = lsadb
+	return res
+}
+
+// Parse the output of show rip interfaces or show rip
+// neighbors. Each RIP protocol is followed by a table of
+// its interfaces or neighbors, which are listed by key.
+// Other messages, e.g. if RIP is not running, are
+// reported as message.
+func parseRipTable(reader io.Reader, key string, parseLine func(string) Parsed) Parsed {
+	protocols := Parsed{}
+	res := Parsed{}
+
+	var protocol Parsed
+	lines := newLineIterator(reader, true)
+	for lines.next() {
+		line := lines.string()
+
+		if specialLine(line) ||
+			strings.HasPrefix(line, "Interface ") ||
+			strings.HasPrefix(line, "IP address ") {
+			continue
+		}
+
+		if groups := regex.rip.protocol.FindStringSubmatch(line); groups != nil {
+			protocol = Parsed{key: []Parsed{}}
+			protocols[groups[1]] = protocol
+			continue
+		}
+
+		entry := parseLine(line)
+		if entry == nil || protocol == nil {
+			res["message"] = strings.TrimSpace(line)
+			continue
+		}
+		protocol[key] = append(protocol[key].([]Parsed), entry)
+	}
+
+	res["protocols"] = protocols
+	return res
+}
+
+// Parse the RIP interfaces by protocol
+func parseRipInterfaces(reader io.Reader) Parsed {
+	return parseRipTable(reader, "interfaces", func(line string) Parsed {
+		groups := regex.rip.iface.FindStringSubmatch(line)
+		if groups == nil {
+			return nil
+		}
+		timer, _ := strconv.ParseFloat(groups[5], 64)
+		return Parsed{
+			"interface": groups[1],
+			"state":     strings.ToLower(groups[2]),
+			"metric":    parseInt(groups[3]),
+			"neighbors": parseInt(groups[4]),
+			"timer":     timer,
+		}
+	})
+}
+
+// Parse the RIP neighbors by protocol. The time a
+// neighbor was last seen is given in seconds ago.
+func parseRipNeighbors(reader io.Reader) Parsed {
+	return parseRipTable(reader, "neighbors", func(line string) Parsed {
+		groups := regex.rip.neighbor.FindStringSubmatch(line)
+		if groups == nil {
+			return nil
+		}
+		seen, _ := strconv.ParseFloat(groups[5], 64)
+		return Parsed{
+			"address":   groups[1],
+			"interface": groups[2],
+			"metric":    parseInt(groups[3]),
+			"routes":    parseInt(groups[4]),
+			"seen":      seen,
+		}
+	})
+}
+
+// Merge the RIP interfaces and neighbors by protocol.
+// Messages, e.g. if RIP is not running, are kept.
+func ripProtocols(interfaces Parsed, neighbors Parsed) Parsed {
+	protocols := Parsed{}
+	for key, res := range map[string]Parsed{"interfaces": interfaces, "neighbors": neighbors} {
+		for name, p := range shortProtocols(res) {
+			protocol, ok := protocols[name].(Parsed)
+			if !ok {
+				protocol = Parsed{"interfaces": []Parsed{}, "neighbors": []Parsed{}}
+				protocols[name] = protocol
+			}
+			protocol[key] = p[key]
+		}
+	}
+
+	rip := Parsed{"protocols": protocols}
+	for _, res := range []Parsed{interfaces, neighbors} {
+		if message, ok := res["message"]; ok {
+			rip["message"] = message
+		}
+	}
+	return rip
+}
+
+// Parse the interfaces known to BIRD. Each interface is
+// followed by a line with its flags and MTU and a line for
+// each of its addresses.
+func parseInterfaces(reader io.Reader) Parsed {
+	interfaces := []Parsed{}
+	var iface Parsed
+
+	lines := newLineIterator(reader, true)
+	for lines.next() {
+		line := lines.string()
+
+		if specialLine(line) {
+			continue
+		}
+
+		if groups := regex.iface.start.FindStringSubmatch(line); groups != nil {
+			iface = Parsed{
+				"name":      groups[1],
+				"state":     strings.ToLower(groups[2]),
+				"index":     parseInt(groups[3]),
+				"addresses": []Parsed{},
+			}
+			if groups[4] != "" {
+				iface["master"] = groups[4]
+			}
+			interfaces = append(interfaces, iface)
+			continue
+		}
+		if iface == nil {
+			continue
+		}
+
+		if groups := regex.iface.address.FindStringSubmatch(line); groups != nil {
+			iface["addresses"] = append(iface["addresses"].([]Parsed), parseInterfaceAddress(groups[1], groups[2]))
+			continue
+		}
+
+		if groups := regex.iface.flags.FindStringSubmatch(line); groups != nil {
+			flags := strings.Fields(groups[1])
+			iface["flags"] = flags
+			iface["admin_up"] = dirtyContains(flags, "AdminUp")
+			iface["link_up"] = dirtyContains(flags, "LinkUp")
+			iface["mtu"] = parseInt(groups[2])
+		}
+	}
+
+	return Parsed{"interfaces": interfaces}
+}
+
+// Parse an address of an interface with its details
+// like "Primary, opposite 10.255.0.2, scope univ".
+func parseInterfaceAddress(prefix string, details string) Parsed {
+	address := Parsed{"prefix": prefix}
+
+	for _, detail := range strings.Split(details, ",") {
+		fields := strings.Fields(detail)
+		switch {
+		case len(fields) == 1:
+			address["type"] = strings.ToLower(fields[0])
+		case len(fields) == 2 && fields[0] == "scope":
+			address["scope"] = fields[1]
+		case len(fields) == 2 && fields[0] == "opposite":
+			address["opposite"] = fields[1]
+		case len(fields) == 2 && fields[0] == "broadcast":
+			address["broadcast"] = fields[1]
+		}
+	}
+
+	return address
 }
 
 func parseRoutesCount(reader io.Reader) Parsed {
@@ -532,8 +1201,11 @@ func parseRoutesCount(reader io.Reader) Parsed {
 		}
 
 		if regex.routeCount.countRx.MatchString(line) {
-			count := regex.routeCount.countRx.FindStringSubmatch(line)[1]
-			res["routes"] = parseInt(count)
+			groups := regex.routeCount.countRx.FindStringSubmatch(line)
+			res["routes"] = parseInt(groups[1])
+			if len(groups[3]) > 0 {
+				res["networks"] = parseInt(groups[3])
+			}
 		}
 	}
 
@@ -552,14 +1224,35 @@ func parseProtocol(lines string) Parsed {
 	res := Parsed{}
 	routeChanges := Parsed{}
 
+	capabilities := ""
+	limit := ""
+
 	handlers := []func(string) bool{
 		func(l string) bool { return parseProtocolHeader(l, res) },
+		func(l string) bool { return parseProtocolBgpRole(l, &capabilities, res) },
+		func(l string) bool { return parseProtocolLimit(l, &limit, res) },
 		func(l string) bool { return parseProtocolRouteLine(l, res) },
 		func(l string) bool { return parseProtocolRouteChanges(l, routeChanges) },
 		func(l string) bool { return parseProtocolNumberValuesRx(l, res) },
 		func(l string) bool { return parseProtocolStringValuesRx(l, res) },
 	}
 
+	// Channels of BIRD 2 protocols are parsed separately,
+	// the lines of a channel are indented deeper than the
+	// channel itself.
+	channels := Parsed{}
+	var channel Parsed
+	channelIndent := 0
+	channelLimit := ""
+
+	channelHandlers := []func(string) bool{
+		func(l string) bool { return parseProtocolLimit(l, &channelLimit, channel) },
+		func(l string) bool { return parseProtocolRouteLine(l, channel) },
+		func(l string) bool { return parseProtocolRouteChanges(l, channel["route_changes"].(Parsed)) },
+		func(l string) bool { return parseProtocolNumberValuesRx(l, channel) },
+		func(l string) bool { return parseProtocolStringValuesRx(l, channel) },
+	}
+
 	ipVersion := ""
 
 	reader := strings.NewReader(lines)
@@ -571,6 +1264,19 @@ func parseProtocol(lines string) Parsed {
 			ipVersion = m[1]
 		}
 
+		if m := regex.protocol.channelName.FindStringSubmatch(line); len(m) > 0 {
+			channel = Parsed{"route_changes": Parsed{}}
+			channelIndent = len(m[1])
+			channelLimit = ""
+			channels[m[2]] = channel
+		} else if channel != nil {
+			if len(line)-len(strings.TrimLeft(line, " \t")) > channelIndent {
+				parseLine(line, channelHandlers)
+			} else {
+				channel = nil
+			}
+		}
+
 		if isCorrectChannel(ipVersion) || ClientConf.Dualstack {
 			parseLine(line, handlers)
 		}
@@ -578,6 +1284,21 @@ func parseProtocol(lines string) Parsed {
 
 	res["route_changes"] = routeChanges
 
+	if len(channels) > 0 {
+		for _, c := range channels {
+			setProtocolChannel(c.(Parsed))
+		}
+		res["channels"] = channels
+	}
+
+	setProtocolStateChanged(res, time.Now())
+	setProtocolFilters(res)
+
+	if res["bird_protocol"] == "BGP" {
+		res["hold_time"] = parseProtocolBgpTimer(res["hold_timer"])
+		res["keepalive"] = parseProtocolBgpTimer(res["keepalive_timer"])
+	}
+
 	if _, ok := res["routes"]; !ok {
 		routes := Parsed{}
 		routes["accepted"] = int64(0)
@@ -592,6 +1313,48 @@ func parseProtocol(lines string) Parsed {
 	return res
 }
 
+// Provide the filters of a channel as import and export
+// filter and the route counts, even if not reported.
+func setProtocolChannel(channel Parsed) {
+	setProtocolFilters(channel)
+
+	if _, ok := channel["routes"]; !ok {
+		channel["routes"] = Parsed{
+			"imported":  int64(0),
+			"exported":  int64(0),
+			"preferred": int64(0),
+		}
+	}
+}
+
+// Normalize the time of the last state change of the
+// protocol. The uptime is derived from it when the
+// response is built, as the result is cached.
+func setProtocolStateChanged(res Parsed, now time.Time) {
+	value, _ := res["state_changed"].(string)
+	changed, ok := ParseTime(value, now)
+	if !ok {
+		return
+	}
+	res["state_changed_at"] = changed.Format(time.RFC3339)
+}
+
+// BIRD reports the filters of a protocol or channel as
+// input and output filter. They are provided as import and
+// export filter, like the filters are configured.
+func setProtocolFilters(res Parsed) {
+	if filter, ok := res["input_filter"]; ok {
+		if _, ok := res["import_filter"]; !ok {
+			res["import_filter"] = filter
+		}
+	}
+	if filter, ok := res["output_filter"]; ok {
+		if _, ok := res["export_filter"]; !ok {
+			res["export_filter"] = filter
+		}
+	}
+}
+
 func parseLine(line string, handlers []func(string) bool) {
 	for _, h := range handlers {
 		if h(line) {
@@ -629,6 +1392,70 @@ func parseProtocolRouteLine(line string, res Parsed) bool {
 	return true
 }
 
+// Parse the BGP roles (RFC 9234) from the local and
+// neighbor capabilities, which are both reported as
+// "Role: rs_server". The current capabilities block
+// is tracked in capabilities.
+func parseProtocolBgpRole(line string, capabilities *string, res Parsed) bool {
+	switch strings.TrimSpace(line) {
+	case "Local capabilities":
+		*capabilities = "local_role"
+		return true
+	case "Neighbor capabilities":
+		*capabilities = "remote_role"
+		return true
+	}
+
+	groups := regex.protocol.bgpRole.FindStringSubmatch(line)
+	if groups == nil || *capabilities == "" {
+		return false
+	}
+
+	res[*capabilities] = groups[1]
+	return true
+}
+
+// Parse route limits like "Import limit: 1000", followed
+// by the action taken when the limit is hit. The action
+// is stored with the limit, e.g. as import_limit_action.
+func parseProtocolLimit(line string, limit *string, res Parsed) bool {
+	if groups := regex.protocol.limit.FindStringSubmatch(line); groups != nil {
+		*limit = strings.ToLower(groups[1]) + "_limit"
+		res[*limit] = parseInt(groups[2])
+		return true
+	}
+
+	groups := regex.protocol.limitAction.FindStringSubmatch(line)
+	if groups == nil || *limit == "" {
+		return false
+	}
+
+	res[*limit+"_action"] = groups[1]
+	*limit = ""
+	return true
+}
+
+// Get the negotiated value from a timer like "151/180".
+// Timers are not available before the session is established.
+func parseProtocolBgpTimer(timer interface{}) interface{} {
+	value, ok := timer.(string)
+	if !ok {
+		return nil
+	}
+
+	tokens := strings.Split(value, "/")
+	if len(tokens) != 2 {
+		return nil
+	}
+
+	negotiated, err := strconv.ParseInt(strings.TrimSpace(tokens[1]), 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	return negotiated
+}
+
 func setChangeCount(name string, value string, res Parsed) {
 	if value == "---" { // field not available for protocol
 		return
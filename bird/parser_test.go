@@ -2,10 +2,13 @@ package bird
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kr/pretty"
 )
@@ -53,6 +56,7 @@ func TestParseStatus(t *testing.T) {
 				"last_reconfig":  "2022-06-03 12:35:43",
 				"message":        "Daemon is up and running",
 				"router_id":      "1.2.3.4",
+				"hostname":       "rs42",
 				"version":        "v2.0.9-11-g207ac485",
 			},
 		},
@@ -65,6 +69,7 @@ func TestParseStatus(t *testing.T) {
 				"last_reconfig":  "2025-03-31 12:34:16.644",
 				"message":        "Daemon is up and running",
 				"router_id": 	  "1.2.3.4",
+				"hostname":       "rs2-par1",
 				"version":        "3.0.1",
 			},
 		},
@@ -133,6 +138,152 @@ func TestParseProtocolBgp(t *testing.T) {
 	fmt.Println(protocols)
 }
 
+func TestParseProtocolLimits(t *testing.T) {
+	f, err := openFile("protocols_bgp_pipe.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	protocols := parseProtocols(f)["protocols"].(Parsed)
+	bgp := protocols["R194_42"].(Parsed)
+	if bgp["import_limit"] != int64(200000) {
+		t.Error("Expected import_limit to be 200000, not", bgp["import_limit"])
+	}
+	if bgp["import_limit_action"] != "disable" {
+		t.Error("Expected import_limit_action to be disable, not", bgp["import_limit_action"])
+	}
+	if _, ok := bgp["action"]; ok {
+		t.Error("Expected the action not to be parsed without the limit")
+	}
+
+	if _, ok := protocols["M65001_nada_co_ripe"].(Parsed)["import_limit"]; ok {
+		t.Error("Expected no import_limit for protocols without limit")
+	}
+}
+
+func TestSetProtocolStateChanged(t *testing.T) {
+	now := time.Date(2018, 6, 1, 15, 38, 40, 0, time.Local)
+
+	up := Parsed{"state": "up", "state_changed": "2018-05-31 15:38:40"}
+	setProtocolStateChanged(up, now)
+	changed := time.Date(2018, 5, 31, 15, 38, 40, 0, time.Local).Format(time.RFC3339)
+	if up["state_changed_at"] != changed {
+		t.Error("Expected state_changed_at to be", changed, "got:", up["state_changed_at"])
+	}
+	if _, ok := up["uptime_seconds"]; ok {
+		t.Error("Expected the uptime not to be parsed")
+	}
+
+	// All protocols are reported with the time of the state change
+	f, err := openFile("protocols_bgp_pipe.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	protocols := parseProtocols(f)["protocols"].(Parsed)
+	for name, p := range protocols {
+		if _, ok := p.(Parsed)["state_changed_at"]; !ok {
+			t.Error("Expected state_changed_at for protocol", name)
+		}
+	}
+}
+
+func TestParseProtocolFilters(t *testing.T) {
+	f, err := openFile("protocols_bgp_pipe.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	protocols := parseProtocols(f)["protocols"].(Parsed)
+	tests := []struct {
+		protocol     string
+		importFilter string
+		exportFilter string
+	}{
+		{"M65001_nada_co_ripe", "in_nada_co_ripe", "(unnamed)"},
+		{"C65003_nada2_co_ripe", "in_nada2_co_ripe", "REJECT"},
+		{"R194_42", "(unnamed)", "(unnamed)"},
+	}
+	for _, test := range tests {
+		protocol := protocols[test.protocol].(Parsed)
+		if protocol["import_filter"] != test.importFilter {
+			t.Error("Expected import_filter", test.importFilter, "for", test.protocol, "got:", protocol["import_filter"])
+		}
+		if protocol["export_filter"] != test.exportFilter {
+			t.Error("Expected export_filter", test.exportFilter, "for", test.protocol, "got:", protocol["export_filter"])
+		}
+	}
+}
+
+func TestParseProtocolBgpRoles(t *testing.T) {
+	f, err := openFile("protocols_bgp_role.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	protocols := parseProtocols(f)["protocols"].(Parsed)
+	bgp := protocols["R192_175"].(Parsed)
+	if bgp["local_role"] != "rs_server" {
+		t.Error("Expected local_role to be rs_server, not", bgp["local_role"])
+	}
+	if bgp["remote_role"] != "rs_client" {
+		t.Error("Expected remote_role to be rs_client, not", bgp["remote_role"])
+	}
+	if bgp["local_as"] != int64(65000) {
+		t.Error("Expected local_as to be 65000, not", bgp["local_as"])
+	}
+
+	// Roles are not reported by older BIRD versions
+	f, err = openFile("protocols_bgp_pipe.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	protocols = parseProtocols(f)["protocols"].(Parsed)
+	if _, ok := protocols["R194_42"].(Parsed)["local_role"]; ok {
+		t.Error("Expected no local_role for BIRD without roles")
+	}
+}
+
+func TestParseProtocolBgpTimers(t *testing.T) {
+	f, err := openFile("protocols_bgp_pipe.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	protocols := parseProtocols(f)["protocols"].(Parsed)
+	bgp := protocols["R194_42"].(Parsed)
+	if bgp["hold_time"] != int64(180) {
+		t.Error("Expected hold_time to be 180, not", bgp["hold_time"])
+	}
+	if bgp["keepalive"] != int64(60) {
+		t.Error("Expected keepalive to be 60, not", bgp["keepalive"])
+	}
+
+	// Timers are not negotiated when the session is down
+	down := parseProtocol(
+		"R194_43  BGP      master   start  2018-05-31 15:38:40  Active\n" +
+			"  BGP state:          Active\n" +
+			"    Neighbor address: 172.31.194.43\n")
+	if v, ok := down["hold_time"]; !ok || v != nil {
+		t.Error("Expected hold_time to be null, not", v)
+	}
+	if v, ok := down["keepalive"]; !ok || v != nil {
+		t.Error("Expected keepalive to be null, not", v)
+	}
+
+	// Bird 2 reports fractional remaining times
+	if timer := parseProtocolBgpTimer("151.427/240"); timer != int64(240) {
+		t.Error("Expected timer to be 240, not", timer)
+	}
+}
+
 func TestParseProtocolShort(t *testing.T) {
 	f, err := openFile("protocols_short.sample")
 	if err != nil {
@@ -171,6 +322,180 @@ func TestParseProtocolShortBird3(t *testing.T) {
 	fmt.Println(protocols)
 }
 
+func TestProtocolsStates(t *testing.T) {
+	f, err := openFile("protocols_short.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	protocols := protocolsStates(parseProtocolsShort(f))["protocols"].(Parsed)
+	if len(protocols) != 27 {
+		t.Fatalf("Expected 27 protocols, found: %v", len(protocols))
+	}
+
+	expected := Parsed{"type": "BGP", "state": "up"}
+	if p := protocols["pb_0097_as3856"]; !reflect.DeepEqual(p, expected) {
+		t.Error("Expected", expected, "got:", p)
+	}
+}
+
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		file     string
+		expected Parsed
+	}{
+		{
+			"memory_bird1.sample",
+			Parsed{
+				"routing_tables":   Parsed{"effective": int64(36 * 1024 * 1024)},
+				"route_attributes": Parsed{"effective": int64(26 * 1024 * 1024)},
+				"roa_tables":       Parsed{"effective": int64(192)},
+				"protocols":        Parsed{"effective": int64(163 * 1024)},
+				"total":            Parsed{"effective": int64(62 * 1024 * 1024)},
+			},
+		},
+		{
+			"memory_bird2.sample",
+			Parsed{
+				"routing_tables":   Parsed{"effective": int64(11219763), "overhead": int64(1920 * 1024)},
+				"route_attributes": Parsed{"effective": int64(15938355), "overhead": int64(8282 * 1024)},
+				"protocols":        Parsed{"effective": int64(4926 * 1024), "overhead": int64(624 * 1024)},
+				"current_config":   Parsed{"effective": int64(100 * 1024), "overhead": int64(50 * 1024)},
+				"standby_memory":   Parsed{"effective": int64(0), "overhead": int64(1312 * 1024)},
+				"total":            Parsed{"effective": int64(31.0 * 1024 * 1024), "overhead": int64(12792627)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		f, err := openFile(test.file)
+		if err != nil {
+			t.Error(err)
+		}
+		memory := parseMemory(f)["memory"].(Parsed)
+		f.Close()
+		if !reflect.DeepEqual(memory, test.expected) {
+			t.Error("Parse memory:", memory, "expected:", test.expected)
+		}
+	}
+}
+
+func TestParseOspfLsadb(t *testing.T) {
+	f, err := openFile("ospf_lsadb.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	lsadb := parseOspfLsadb(f)["lsadb"].([]Parsed)
+	if len(lsadb) != 8 {
+		t.Fatal("Expected 8 LSAs, got:", len(lsadb))
+	}
+
+	expected := Parsed{
+		"type":     "0002",
+		"ls_id":    "10.0.0.2",
+		"router":   "192.168.1.2",
+		"sequence": "80000001",
+		"age":      int64(589),
+		"checksum": "c4e3",
+		"scope":    "area",
+		"area":     "0.0.0.0",
+	}
+	if !reflect.DeepEqual(lsadb[4], expected) {
+		t.Error("Expected LSA:", expected, "got:", lsadb[4])
+	}
+
+	if lsadb[0]["scope"] != "global" {
+		t.Error("Expected global scope, got:", lsadb[0]["scope"])
+	}
+	if lsadb[5]["area"] != "0.0.0.1" {
+		t.Error("Expected area 0.0.0.1, got:", lsadb[5]["area"])
+	}
+	if lsadb[7]["interface"] != "eth0" {
+		t.Error("Expected link scope on eth0, got:", lsadb[7])
+	}
+
+	// No LSAs are reported without OSPF
+	f, err = openFile("ospf_lsadb_not_running.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	res := parseOspfLsadb(f)
+	if len(res["lsadb"].([]Parsed)) != 0 {
+		t.Error("Expected no LSAs, got:", res["lsadb"])
+	}
+	if res["message"] != "There is no OSPF protocol running" {
+		t.Error("Unexpected message:", res["message"])
+	}
+}
+
+func TestParseRoutesBird3ParseErrors(t *testing.T) {
+	ParserConf.ReportParseErrors = true
+	defer func() { ParserConf.ReportParseErrors = false }()
+
+	for _, sample := range []string{"routes_bird3_ipv4.sample", "routes_bird3_ipv6.sample"} {
+		f, err := openFile(sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := parseRoutes(f)
+		f.Close()
+
+		if _, ok := res[parseErrorCountKey]; ok {
+			t.Error("Expected all lines of", sample, "to be parsed, got:", res[parseErrorCountKey])
+		}
+		if _, ok := res[ParseErrorsKey]; ok {
+			t.Error("Expected no parse errors for", sample, "got:", res[ParseErrorsKey])
+		}
+	}
+}
+
+func TestParseRoutesParseErrors(t *testing.T) {
+	f, err := openFile("routes_bird2_multipath.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	ParserConf.ReportParseErrors = true
+	defer func() { ParserConf.ReportParseErrors = false }()
+
+	res := parseRoutes(io.MultiReader(f, strings.NewReader(
+		"\tUnknown.attribute: 1\n\tUnknown.attribute: 2\n")))
+	if len(res["routes"].([]Parsed)) == 0 {
+		t.Error("Expected routes to be parsed")
+	}
+	parseErrors, ok := res[ParseErrorsKey].([]Parsed)
+	if !ok || len(parseErrors) != 2 {
+		t.Fatal("Expected 2 parse errors, got:", res[ParseErrorsKey])
+	}
+	if parseErrors[0]["line"] != "\tUnknown.attribute: 1" {
+		t.Error("Unexpected line in parse error:", parseErrors[0]["line"])
+	}
+
+	// Parse errors are not reported by default
+	ParserConf.ReportParseErrors = false
+	f.Seek(0, 0)
+	if _, ok := parseRoutes(f)[ParseErrorsKey]; ok {
+		t.Error("Expected no parse errors to be reported")
+	}
+}
+
+func TestParseRoutesCount(t *testing.T) {
+	count := parseRoutesCount(strings.NewReader(
+		"BIRD 2.0.7 ready.\n1124 of 1124 routes for 1035 networks in table master4\n"))
+	if count["routes"] != int64(1124) {
+		t.Error("Expected routes to be 1124, not", count["routes"])
+	}
+	if count["networks"] != int64(1035) {
+		t.Error("Expected networks to be 1035, not", count["networks"])
+	}
+}
+
 func TestParseRoutesAllIpv4Bird1(t *testing.T) {
 	runTestForIpv4WithFile("routes_bird1_ipv4.sample", t)
 }
@@ -488,6 +813,116 @@ func runTestForIpv6WithFile(file string, t *testing.T) {
 	}, routes[2], "Route 3", t)
 }
 
+func TestParseRoutesMultipath(t *testing.T) {
+	f, err := openFile("routes_bird2_multipath.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	routes, ok := parseRoutes(f)["routes"].([]Parsed)
+	if !ok {
+		t.Fatal("Error getting routes")
+	}
+	if len(routes) != 2 {
+		t.Fatal("Expected 2 routes but got ", len(routes))
+	}
+
+	expected := []Parsed{
+		{"gateway": "192.168.1.1", "interface": "eth0", "weight": int64(1)},
+		{"gateway": "192.168.2.1", "interface": "eth1", "weight": int64(3)},
+	}
+	if nextHops := routes[0]["next_hops"]; !reflect.DeepEqual(nextHops, expected) {
+		t.Error("Expected next_hops to be:", expected, "not", nextHops)
+	}
+	if routes[0]["gateway"] != "192.168.1.1" || routes[0]["interface"] != "eth0" {
+		t.Error("Expected gateway of first next hop, not", routes[0]["gateway"])
+	}
+	if routes[0]["metric"] != int64(200) {
+		t.Error("Expected metric to be 200, not", routes[0]["metric"])
+	}
+
+	expected = []Parsed{
+		{"gateway": "192.168.1.2", "interface": "eth0", "weight": int64(1)},
+	}
+	if nextHops := routes[1]["next_hops"]; !reflect.DeepEqual(nextHops, expected) {
+		t.Error("Expected next_hops to be:", expected, "not", nextHops)
+	}
+}
+
+func TestParseRoutesTables(t *testing.T) {
+	f, err := openFile("routes_bird2_tables.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	routes, ok := parseRoutes(f)["routes"].([]Parsed)
+	if !ok {
+		t.Fatal("Error getting routes")
+	}
+	if len(routes) != 3 {
+		t.Fatal("Expected 3 routes but got ", len(routes))
+	}
+
+	expected := []string{"master4", "tenant1", "tenant1"}
+	for i, route := range routes {
+		if route["table"] != expected[i] {
+			t.Error("Expected route", i, "from table", expected[i], "not", route["table"])
+		}
+	}
+	if routes[2]["from_protocol"] != "bgp2" {
+		t.Error("Expected second path from bgp2, not", routes[2]["from_protocol"])
+	}
+}
+
+func TestParseRoutesVpn(t *testing.T) {
+	f, err := openFile("routes_bird2_vpn4.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	routes, ok := parseRoutes(f)["routes"].([]Parsed)
+	if !ok {
+		t.Fatal("Error getting routes")
+	}
+	if len(routes) != 3 {
+		t.Fatal("Expected 3 routes but got ", len(routes))
+	}
+
+	tests := []struct {
+		network       string
+		distinguisher interface{}
+		labels        interface{}
+		targets       interface{}
+	}{
+		{"10.0.0.0/24", "1:1", []int64{100}, []string{"65000:100"}},
+		{"10.0.0.0/24", "1:1", []int64{200, 300}, []string{"65000:100", "10.0.0.1:5"}},
+		{"10.1.0.0/24", nil, nil, nil},
+	}
+	for i, test := range tests {
+		route := routes[i]
+		bgp := route["bgp"].(Parsed)
+		if route["network"] != test.network {
+			t.Error("Expected network to be", test.network, "not", route["network"])
+		}
+		if !reflect.DeepEqual(route["route_distinguisher"], test.distinguisher) {
+			t.Error("Expected route_distinguisher to be", test.distinguisher, "not", route["route_distinguisher"])
+		}
+		if !reflect.DeepEqual(route["mpls_labels"], test.labels) {
+			t.Error("Expected mpls_labels to be", test.labels, "not", route["mpls_labels"])
+		}
+		if !reflect.DeepEqual(bgp["route_targets"], test.targets) {
+			t.Error("Expected route_targets to be", test.targets, "not", bgp["route_targets"])
+		}
+	}
+
+	if stack := routes[0]["bgp"].(Parsed)["mpls_label_stack"]; !reflect.DeepEqual(stack, []int64{100}) {
+		t.Error("Expected mpls_label_stack to be [100], not", stack)
+	}
+}
+
 func assertRouteIsEqual(expected expectedRoute, actual Parsed, name string, t *testing.T) {
 	if prefix := value(actual, "network", name, t).(string); prefix != expected.network {
 		t.Fatal(name, ": Expected network to be:", expected.network, "not", prefix)
@@ -555,3 +990,350 @@ type expectedRoute struct {
 	localPref           string
 	iface               string
 }
+
+func TestParseInterfaces(t *testing.T) {
+	f, err := openFile("interfaces.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	interfaces := parseInterfaces(f)["interfaces"].([]Parsed)
+	if len(interfaces) != 4 {
+		t.Fatal("Expected 4 interfaces, got:", len(interfaces))
+	}
+
+	eth0 := interfaces[1]
+	if eth0["name"] != "eth0" || eth0["state"] != "up" || eth0["index"] != int64(2) {
+		t.Error("Unexpected interface:", eth0)
+	}
+	if eth0["mtu"] != int64(1500) || eth0["admin_up"] != true || eth0["link_up"] != true {
+		t.Error("Unexpected flags of interface:", eth0)
+	}
+	addresses := eth0["addresses"].([]Parsed)
+	if len(addresses) != 4 {
+		t.Fatal("Expected 4 addresses, got:", len(addresses))
+	}
+	expected := Parsed{
+		"prefix":    "198.51.100.10/24",
+		"type":      "secondary",
+		"broadcast": "198.51.100.255",
+		"scope":     "univ",
+	}
+	if !reflect.DeepEqual(addresses[1], expected) {
+		t.Error("Expected address:", expected, "got:", addresses[1])
+	}
+
+	wg0 := interfaces[2]["addresses"].([]Parsed)[0]
+	if wg0["opposite"] != "10.255.0.2" {
+		t.Error("Expected opposite address, got:", wg0)
+	}
+
+	eth1 := interfaces[3]
+	if eth1["state"] != "down" || eth1["master"] != "br0" || eth1["link_up"] != false {
+		t.Error("Unexpected interface:", eth1)
+	}
+	if len(eth1["addresses"].([]Parsed)) != 0 {
+		t.Error("Expected no addresses, got:", eth1["addresses"])
+	}
+}
+
+func TestParseProtocolChannels(t *testing.T) {
+	f, err := openFile("protocols_bird2_channels.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	protocols := parseProtocols(f)["protocols"].(Parsed)
+	if len(protocols) != 3 {
+		t.Fatal("Expected 3 protocols, got:", len(protocols))
+	}
+
+	channels := protocols["R194_42"].(Parsed)["channels"].(Parsed)
+	if len(channels) != 2 {
+		t.Fatal("Expected 2 channels, got:", channels)
+	}
+
+	ipv4 := channels["ipv4"].(Parsed)
+	expected := Parsed{
+		"state":               "UP",
+		"table":               "master4",
+		"import_filter":       "peer_in_v4",
+		"export_filter":       "peer_out_v4",
+		"import_limit":        int64(1000),
+		"import_limit_action": "disable",
+		"bgp_next_hop":        "192.0.2.1",
+	}
+	for k, v := range expected {
+		if ipv4[k] != v {
+			t.Error("Expected", k, "of channel ipv4 to be", v, "got:", ipv4[k])
+		}
+	}
+	routes := Parsed{"imported": int64(812), "exported": int64(14), "preferred": int64(790)}
+	if !reflect.DeepEqual(ipv4["routes"], routes) {
+		t.Error("Expected routes:", routes, "got:", ipv4["routes"])
+	}
+	updates := ipv4["route_changes"].(Parsed)["import_updates"].(Parsed)
+	if updates["filtered"] != int64(38) || updates["accepted"] != int64(986) {
+		t.Error("Unexpected import updates:", updates)
+	}
+
+	ipv6 := channels["ipv6"].(Parsed)
+	if ipv6["table"] != "master6" || ipv6["import_filter"] != "peer_in_v6" {
+		t.Error("Unexpected channel ipv6:", ipv6)
+	}
+	if ipv6["bgp_next_hop"] != "2001:db8:0:1::1 fe80::1" {
+		t.Error("Unexpected next hop of channel ipv6:", ipv6["bgp_next_hop"])
+	}
+	if _, ok := ipv6["import_limit"]; ok {
+		t.Error("Expected no import limit for channel ipv6")
+	}
+
+	// Protocol level details are not part of the channels
+	if _, ok := ipv4["neighbor_address"]; ok {
+		t.Error("Expected no neighbor address in channel ipv4")
+	}
+
+	if _, ok := protocols["device1"].(Parsed)["channels"]; ok {
+		t.Error("Expected no channels for device protocol")
+	}
+	kernel := protocols["kernel1"].(Parsed)["channels"].(Parsed)["ipv4"].(Parsed)
+	if kernel["routes"].(Parsed)["exported"] != int64(904) {
+		t.Error("Unexpected routes of kernel channel:", kernel["routes"])
+	}
+}
+
+func TestParseRoutesBgpNextHop(t *testing.T) {
+	f, err := openFile("routes_bird2_ipv4.sample")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	route := parseRoutes(f)["routes"].([]Parsed)[0]
+	if route["bgp_next_hop"] != "1.2.3.16" || route["gateway"] != "1.2.3.16" {
+		t.Error("Unexpected next hops of route:", route["bgp_next_hop"], route["gateway"])
+	}
+
+	// Routes learned by other protocols have no BGP next hop
+	f, err = openFile("routes_bird2_multipath.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	route = parseRoutes(f)["routes"].([]Parsed)[0]
+	if _, ok := route["bgp_next_hop"]; ok {
+		t.Error("Expected no BGP next hop, got:", route["bgp_next_hop"])
+	}
+
+	// A resolved next hop differs from the gateway
+	route = Parsed{"gateway": "192.0.2.1"}
+	setRouteBgpNextHop(route, Parsed{"next_hop": "2001:db8::42 fe80::42"})
+	if route["bgp_next_hop"] != "2001:db8::42" || route["bgp_next_hop_link_local"] != "fe80::42" {
+		t.Error("Unexpected BGP next hop:", route)
+	}
+	if route["gateway"] != "192.0.2.1" {
+		t.Error("Expected the gateway to be kept, got:", route["gateway"])
+	}
+}
+
+func TestParseRoutesDevice(t *testing.T) {
+	f, err := openFile("routes_bird2_direct.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	routes, ok := parseRoutes(f)["routes"].([]Parsed)
+	if !ok {
+		t.Fatal("Error getting routes")
+	}
+	if len(routes) != 2 {
+		t.Fatal("Expected 2 routes but got ", len(routes))
+	}
+
+	if routes[0]["interface"] != "eth0" {
+		t.Error("Expected device route on eth0, not", routes[0]["interface"])
+	}
+	if _, ok := routes[0]["gateway"]; ok {
+		t.Error("Expected no gateway of the device route:", routes[0]["gateway"])
+	}
+	if routes[1]["interface"] != "eth0" || routes[1]["gateway"] != "192.168.1.1" {
+		t.Error("Unexpected route:", routes[1])
+	}
+}
+
+func TestParseRoutesPreference(t *testing.T) {
+	f, err := openFile("routes_bird2_preference.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	parsed := parseRoutes(f)
+	if _, ok := parsed[parseErrorCountKey]; ok {
+		t.Error("Expected all lines to be parsed, got:", parsed[parseErrorCountKey])
+	}
+	routes, ok := parsed["routes"].([]Parsed)
+	if !ok || len(routes) != 4 {
+		t.Fatal("Expected 4 routes, got:", parsed["routes"])
+	}
+
+	// Tied preference, the primary route has the lower metric
+	for i, metric := range []int64{10, 20} {
+		route := routes[i]
+		if route["preference"] != int64(150) || route["igp_metric"] != metric {
+			t.Error("Expected preference 150 and metric", metric, "got:", route)
+		}
+		ospf := route["ospf"].(Parsed)
+		if ospf["metric1"] != metric || ospf["metric2"] != int64(10000) {
+			t.Error("Expected OSPF metrics", metric, "and 10000, got:", ospf)
+		}
+		if ospf["tag"] != "0x00000000" {
+			t.Error("Expected the OSPF tag as string, got:", ospf["tag"])
+		}
+	}
+	if routes[0]["primary"] != true || routes[1]["primary"] != false {
+		t.Error("Expected the first OSPF route to be primary")
+	}
+
+	// Unknown metrics are omitted
+	if _, ok := routes[2]["igp_metric"]; ok || routes[2]["preference"] != int64(100) {
+		t.Error("Expected preference 100 without metric, got:", routes[2])
+	}
+	if routes[3]["preference"] != int64(100) || routes[3]["igp_metric"] != int64(10) {
+		t.Error("Expected preference 100 and metric 10, got:", routes[3])
+	}
+}
+
+func TestParseRip(t *testing.T) {
+	parse := func(filename string, parser func(io.Reader) Parsed) Parsed {
+		f, err := openFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		return parser(f)
+	}
+
+	rip := ripProtocols(
+		parse("rip_interfaces.sample", parseRipInterfaces),
+		parse("rip_neighbors.sample", parseRipNeighbors))
+	protocols := rip["protocols"].(Parsed)
+	if len(protocols) != 2 {
+		t.Fatal("Expected 2 RIP protocols, got:", protocols)
+	}
+
+	rip1 := protocols["rip1"].(Parsed)
+	interfaces := rip1["interfaces"].([]Parsed)
+	expected := Parsed{
+		"interface": "eth0",
+		"state":     "up",
+		"metric":    int64(1),
+		"neighbors": int64(2),
+		"timer":     12.345,
+	}
+	if len(interfaces) != 2 || !reflect.DeepEqual(interfaces[0], expected) {
+		t.Error("Expected interface:", expected, "got:", interfaces)
+	}
+	if interfaces[1]["state"] != "down" {
+		t.Error("Expected eth1 to be down, got:", interfaces[1])
+	}
+
+	neighbors := rip1["neighbors"].([]Parsed)
+	expected = Parsed{
+		"address":   "10.0.0.3",
+		"interface": "eth0",
+		"metric":    int64(1),
+		"routes":    int64(3),
+		"seen":      21.004,
+	}
+	if len(neighbors) != 2 || !reflect.DeepEqual(neighbors[1], expected) {
+		t.Error("Expected neighbor:", expected, "got:", neighbors)
+	}
+
+	ripng := protocols["rip_ng"].(Parsed)
+	if n := ripng["neighbors"].([]Parsed); len(n) != 1 || n[0]["address"] != "fe80::2" {
+		t.Error("Expected the IPv6 neighbor of rip_ng, got:", n)
+	}
+
+	// No protocols are listed without RIP
+	notRunning := parse("rip_not_running.sample", parseRipNeighbors)
+	rip = ripProtocols(notRunning, notRunning)
+	if len(rip["protocols"].(Parsed)) != 0 || rip["message"] != "There is no RIP protocol running" {
+		t.Error("Expected no protocols and the message, got:", rip)
+	}
+}
+
+func TestBgpSummary(t *testing.T) {
+	f, err := openFile("protocols_short.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	summary := bgpSummary(parseProtocolsShort(f))["summary"].(Parsed)
+	if len(summary) != len(bgpSummaryBuckets) {
+		t.Fatal("Expected all buckets, got:", summary)
+	}
+
+	idle := summary["idle"].(Parsed)
+	expected := []string{"R194_205", "R_janus1"}
+	if idle["count"] != 2 || !reflect.DeepEqual(idle["protocols"], expected) {
+		t.Error("Unexpected idle sessions:", idle)
+	}
+	if summary["up"].(Parsed)["count"] == 0 {
+		t.Error("Expected established sessions to be up")
+	}
+	if summary["connect"].(Parsed)["count"] != 0 {
+		t.Error("Expected no connecting sessions:", summary["connect"])
+	}
+
+	tests := []struct {
+		state  string
+		info   string
+		bucket string
+	}{
+		{"start", "Active        Socket: Connection refused", "active"},
+		{"start", "Connect", "connect"},
+		{"start", "OpenSent", "connect"},
+		{"down", "", "down"},
+		{"start", "", "idle"},
+	}
+	for _, test := range tests {
+		if bucket := bgpSessionBucket(test.state, test.info); bucket != test.bucket {
+			t.Error("Expected", test.state, test.info, "in", test.bucket, "got:", bucket)
+		}
+	}
+}
+
+func TestParseRoutesID(t *testing.T) {
+	f, err := openFile("routes_bird2_tables.sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	routes := parseRoutes(f)["routes"].([]Parsed)
+	ids := map[int64]bool{}
+	for _, route := range routes {
+		id, ok := route["id"].(int64)
+		if !ok || id < 0 || id > routeIDMask {
+			t.Fatal("Unexpected route id:", route["id"])
+		}
+		ids[id] = true
+	}
+	if len(ids) != len(routes) {
+		t.Error("Expected distinct ids of the routes, got:", ids)
+	}
+
+	// The id is stable across responses
+	f.Seek(0, 0)
+	again := parseRoutes(f)["routes"].([]Parsed)
+	for i, route := range again {
+		if route["id"] != routes[i]["id"] {
+			t.Error("Expected the same id for route", i, "got:", route["id"], routes[i]["id"])
+		}
+	}
+}
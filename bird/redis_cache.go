@@ -2,6 +2,8 @@ package bird
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -13,6 +15,9 @@ import (
 type RedisCache struct {
 	client    *redis.Client
 	keyPrefix string
+	staleTime time.Duration // Retention of expired keys
+	jitter    float64       // Random spread of the TTL
+	hashKeys  bool          // Store keys as SHA-256 hash
 }
 
 func NewRedisCache(config CacheConfig) (*RedisCache, error) {
@@ -30,35 +35,60 @@ func NewRedisCache(config CacheConfig) (*RedisCache, error) {
 	}
 
 	cache := &RedisCache{
-		client: client,
+		client:    client,
+		staleTime: time.Duration(config.StaleWhileError) * time.Minute,
+		jitter:    ttlJitter(),
+		hashKeys:  config.RedisHashKeys,
 	}
 
 	return cache, nil
 }
 
+// Get the key in redis for a cache key. Keys are hashed
+// if configured, bounding the length of the keys.
+func (self *RedisCache) redisKey(key string) string {
+	if self.hashKeys {
+		sum := sha256.Sum256([]byte(key))
+		key = hex.EncodeToString(sum[:])
+	}
+	return self.keyPrefix + key //"B" + IPVersion + "_" + key
+}
+
 // Get retrievs a birdwatcher `Parsed` result from
 // the redis cache.
 func (self *RedisCache) Get(key string) (Parsed, error) {
+	return self.get(key, 0)
+}
+
+// GetStale retrieves a result from the redis cache,
+// even if the TTL is expired, as long as it is retained.
+func (self *RedisCache) GetStale(key string) (Parsed, error) {
+	return self.get(key, self.staleTime)
+}
+
+func (self *RedisCache) get(key string, staleTime time.Duration) (Parsed, error) {
 	ctx := context.Background()
-	key = self.keyPrefix + key //"B" + IPVersion + "_" + key
+	key = self.redisKey(key)
 	data, err := self.client.Get(ctx, key).Result()
 	if err != nil {
 		return NilParse, err
 	}
 
 	parsed := Parsed{}
-	err = json.Unmarshal([]byte(data), &parsed)
+	if err := json.Unmarshal([]byte(data), &parsed); err != nil {
+		return NilParse, err
+	}
 
 	ttl, err := parseCacheTTL(parsed["ttl"])
 	if err != nil {
 		return NilParse, fmt.Errorf("invalid TTL value for key: %s", key)
 	}
 	// Deal with the inband TTL if present
-	if !ttl.Equal(time.Time{}) && ttl.Before(time.Now()) {
-		return NilParse, err // TTL expired
+	if !ttl.Equal(time.Time{}) && ttl.Add(staleTime).Before(time.Now()) {
+		return NilParse, fmt.Errorf("TTL expired for key: %s", key)
 	}
 
-	return parsed, err // cache hit
+	return parsed, nil // cache hit
 }
 
 // Set adds a birdwatcher `Parsed` result
@@ -69,7 +99,15 @@ func (self *RedisCache) Set(key string, parsed Parsed, ttl int) error {
 		return nil // do not cache
 
 	case ttl > 0:
-		key = self.keyPrefix + key //TODO "B" + IPVersion + "_" + key
+		key = self.redisKey(key)
+
+		// The inband TTL is required to tell expired
+		// from stale entries, which redis retains.
+		cachedAt := time.Now().UTC()
+		expiry := jitterTtl(time.Duration(ttl)*time.Minute, self.jitter)
+		parsed["ttl"] = cachedAt.Add(expiry)
+		parsed["cached_at"] = cachedAt
+
 		payload, err := json.Marshal(parsed)
 		if err != nil {
 			return err
@@ -77,7 +115,7 @@ func (self *RedisCache) Set(key string, parsed Parsed, ttl int) error {
 
 		ctx := context.Background()
 		_, err = self.client.Set(
-			ctx, key, payload, time.Duration(ttl)*time.Minute).Result()
+			ctx, key, payload, expiry+self.staleTime).Result()
 		return err
 
 	default: // ttl negative - invalid
@@ -1,6 +1,8 @@
 package bird
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -100,3 +102,99 @@ func Test_RedisCacheRoutes(t *testing.T) {
 
 	t.Log("Retrieved routes:", len(routes))
 }
+
+func TestRedisCacheExpired(t *testing.T) {
+	cache, err := NewRedisCache(CacheConfig{
+		RedisServer:     "localhost:6379",
+		StaleWhileError: 5,
+	})
+
+	if err != nil {
+		t.Log("Redis server not available:", err)
+		t.Log("Skipping redis tests.")
+		return
+	}
+
+	// The key is retained for the stale while error time
+	// after its TTL expired
+	payload, _ := json.Marshal(Parsed{
+		"foo": 23,
+		"ttl": time.Now().Add(-time.Minute),
+	})
+	ctx := context.Background()
+	if err := cache.client.Set(ctx, "expired_test", payload, time.Minute).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if val, err := cache.Get("expired_test"); err == nil {
+		t.Error("Expected a miss for the expired key, got:", val)
+	}
+	if val, err := cache.GetStale("expired_test"); err != nil || val["foo"] == nil {
+		t.Error("Expected a stale hit for the expired key, got:", val, err)
+	}
+
+	// The memory tier does not keep the expired key
+	tiered := NewTieredCache(cache, 10, time.Minute)
+	if val, err := tiered.Get("expired_test"); err == nil {
+		t.Error("Expected a miss in the memory tier, got:", val)
+	}
+	if n := tiered.Len(); n != 0 {
+		t.Error("Expected no keys in memory, got:", n)
+	}
+	if _, err := tiered.GetStale("expired_test"); err != nil {
+		t.Error("Expected a stale hit in the memory tier, got:", err)
+	}
+}
+
+func TestRedisRetryInterval(t *testing.T) {
+	CacheConf = CacheConfig{RedisRetryInterval: 2}
+	defer func() { CacheConf = CacheConfig{} }()
+
+	expected := []time.Duration{
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		16 * time.Second,
+		30 * time.Second,
+		30 * time.Second,
+	}
+	for attempt, interval := range expected {
+		if i := redisRetryInterval(attempt); i != interval {
+			t.Error("Expected interval", interval, "for attempt", attempt, "got:", i)
+		}
+	}
+}
+
+func TestInitializeCacheRedisFallback(t *testing.T) {
+	CacheConf = CacheConfig{
+		UseRedis:      true,
+		RedisServer:   "127.0.0.1:1", // Nothing is listening here
+		RedisFallback: true,
+	}
+	defer func() { CacheConf = CacheConfig{} }()
+
+	InitializeCache()
+	if _, ok := cache.(*MemoryCache); !ok {
+		t.Error("Expected to fall back to the MemoryCache, got:", cache)
+	}
+}
+
+func TestRedisCacheKey(t *testing.T) {
+	cache := &RedisCache{}
+	key := "route all for 10.0.0.0/8 table 'master4'"
+	if cache.redisKey(key) != key {
+		t.Error("Expected the key to be kept, got:", cache.redisKey(key))
+	}
+
+	cache.hashKeys = true
+	hashed := cache.redisKey(key)
+	if len(hashed) != 64 {
+		t.Error("Expected a SHA-256 hex key, got:", hashed)
+	}
+	if cache.redisKey(key) != hashed {
+		t.Error("Expected the hashed key to be stable")
+	}
+	if cache.redisKey("route all") == hashed {
+		t.Error("Expected different keys for different commands")
+	}
+}
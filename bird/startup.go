@@ -0,0 +1,32 @@
+package bird
+
+// Check of the birdc command at startup
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Timeout of the status command of the startup check
+const startupStatusTimeout = 5 * time.Second
+
+// CheckBirdc verifies that the birdc binary of the config
+// exists and is executable. With checkStatus, birdc is run
+// with "show status" to check it can reach BIRD.
+func CheckBirdc(checkStatus bool) error {
+	birdc := strings.Split(ClientConf.BirdCmd, " ")[0]
+	path, err := exec.LookPath(birdc)
+	if err != nil {
+		return fmt.Errorf("birdc %q is not an executable: %s", birdc, err)
+	}
+	if !checkStatus {
+		return nil
+	}
+
+	if _, err := runWithLimits("status", RunLimits{Timeout: startupStatusTimeout}); err != nil {
+		return fmt.Errorf("%s show status failed: %s", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package bird
+
+import (
+	"testing"
+)
+
+func TestCheckBirdc(t *testing.T) {
+	defer func() { ClientConf = BirdConfig{} }()
+
+	tests := []struct {
+		birdc       string
+		checkStatus bool
+		ok          bool
+	}{
+		{"/nonexistent/birdc", false, false},
+		{"true -s /run/bird.ctl", false, true},
+		{"true", true, true},
+		{"false", false, true},
+		{"false", true, false},
+	}
+	for _, test := range tests {
+		ClientConf.BirdCmd = test.birdc
+		err := CheckBirdc(test.checkStatus)
+		if (err == nil) != test.ok {
+			t.Error("Unexpected result of", test.birdc, test.checkStatus, ":", err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package bird
+
+import (
+	"sync/atomic"
+)
+
+// Stats are internal counters of the client
+// for introspection.
+type Stats struct {
+	// Number of keys cached in memory, -1
+	// for redis without memory tier
+	CacheSize int `json:"cache_size"`
+
+	WorkerPoolSize int   `json:"worker_pool_size"`
+	WorkersBusy    int64 `json:"workers_busy"`
+
+	BirdcRuns    int64   `json:"birdc_runs"`
+	BirdcSeconds float64 `json:"birdc_seconds"`
+}
+
+// GetStats gets the current counters
+func GetStats() Stats {
+	cacheSize := -1
+	if c, ok := cache.(interface{ Len() int }); ok {
+		cacheSize = c.Len()
+	}
+
+	return Stats{
+		CacheSize:      cacheSize,
+		WorkerPoolSize: WorkerPoolSize,
+		WorkersBusy:    atomic.LoadInt64(&workersBusy),
+		BirdcRuns:      int64(birdcRuns.Value()),
+		BirdcSeconds:   birdcSeconds.Value(),
+	}
+}
@@ -0,0 +1,133 @@
+package bird
+
+import (
+	"sync"
+	"time"
+)
+
+// TieredCache keeps the results of a shared cache, like redis,
+// for a short time in memory, so frequently requested keys are
+// not fetched from the shared cache every time. Results are
+// written to both tiers. Stale results are only served by the
+// shared cache.
+type TieredCache struct {
+	sync.Mutex
+	shared Cache
+
+	entries map[string]tieredCacheEntry
+	maxKeys int
+	ttl     time.Duration
+}
+
+// An entry of the memory tier
+type tieredCacheEntry struct {
+	val     Parsed
+	expires time.Time
+}
+
+// NewTieredCache creates a memory tier of up to maxKeys
+// keys with the TTL in front of the shared cache.
+func NewTieredCache(shared Cache, maxKeys int, ttl time.Duration) *TieredCache {
+	return &TieredCache{
+		shared:  shared,
+		entries: map[string]tieredCacheEntry{},
+		maxKeys: maxKeys,
+		ttl:     ttl,
+	}
+}
+
+// Keep a result in memory. It expires with the memory
+// TTL or its TTL in the shared cache, whichever is first.
+func (c *TieredCache) remember(key string, val Parsed) {
+	now := time.Now()
+	expires := now.Add(c.ttl)
+	if ttl, err := parseCacheTTL(val["ttl"]); err == nil && !ttl.IsZero() && ttl.Before(expires) {
+		expires = ttl
+	}
+	if !expires.After(now) {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxKeys {
+		c.evict(now)
+	}
+	c.entries[key] = tieredCacheEntry{val: val, expires: expires}
+}
+
+// Remove the expired entries or, if none expired,
+// the entry expiring first. The cache must be locked.
+func (c *TieredCache) evict(now time.Time) {
+	first := ""
+	for key, entry := range c.entries {
+		switch {
+		case entry.expires.Before(now):
+			delete(c.entries, key)
+		case first == "" || entry.expires.Before(c.entries[first].expires):
+			first = key
+		}
+	}
+	if len(c.entries) >= c.maxKeys {
+		delete(c.entries, first)
+	}
+}
+
+// Get a key from memory or the shared cache. Results
+// of the shared cache are remembered.
+func (c *TieredCache) Get(key string) (Parsed, error) {
+	c.Lock()
+	entry, ok := c.entries[key]
+	c.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.val, nil
+	}
+
+	val, err := c.shared.Get(key)
+	if err != nil {
+		return val, err
+	}
+	c.remember(key, val)
+	return val, nil
+}
+
+// GetStale gets a key from the shared cache,
+// even if the TTL is expired.
+func (c *TieredCache) GetStale(key string) (Parsed, error) {
+	return c.shared.GetStale(key)
+}
+
+// Set a key in the shared cache and in memory
+func (c *TieredCache) Set(key string, val Parsed, ttl int) error {
+	if err := c.shared.Set(key, val, ttl); err != nil {
+		return err
+	}
+	if ttl > 0 {
+		c.remember(key, val)
+	}
+	return nil
+}
+
+// Expire the entries of the memory tier, the shared
+// cache expires its entries by itself.
+func (c *TieredCache) Expire() int {
+	c.Lock()
+	defer c.Unlock()
+
+	now := time.Now()
+	expired := 0
+	for key, entry := range c.entries {
+		if entry.expires.Before(now) {
+			delete(c.entries, key)
+			expired++
+		}
+	}
+	return expired
+}
+
+// Len gets the number of keys in memory
+func (c *TieredCache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+	return len(c.entries)
+}
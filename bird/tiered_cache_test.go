@@ -0,0 +1,99 @@
+package bird
+
+import (
+	"testing"
+	"time"
+)
+
+// Count the lookups of the shared cache
+type countingCache struct {
+	*MemoryCache
+	gets int
+}
+
+func (c *countingCache) Get(key string) (Parsed, error) {
+	c.gets++
+	return c.MemoryCache.Get(key)
+}
+
+func TestTieredCache(t *testing.T) {
+	shared := &countingCache{MemoryCache: NewMemoryCache(100)}
+	shared.Set("hot", Parsed{"foo": 23}, 5)
+
+	cache := NewTieredCache(shared, 2, time.Minute)
+	for i := 0; i < 3; i++ {
+		val, err := cache.Get("hot")
+		if err != nil || val["foo"] != 23 {
+			t.Fatal("Expected the cached result, got:", val, err)
+		}
+	}
+	if shared.gets != 1 {
+		t.Error("Expected the shared cache to be asked once, got:", shared.gets)
+	}
+
+	if _, err := cache.Get("missing"); err == nil {
+		t.Error("Expected a miss for a missing key")
+	}
+
+	// Results are written to both tiers
+	cache.Set("written", Parsed{"bar": 42}, 5)
+	if _, err := shared.MemoryCache.Get("written"); err != nil {
+		t.Error("Expected the result in the shared cache:", err)
+	}
+	gets := shared.gets
+	cache.Get("written")
+	if shared.gets != gets {
+		t.Error("Expected the written result in memory")
+	}
+
+	// The memory tier is limited
+	cache.Set("third", Parsed{}, 5)
+	if n := cache.Len(); n != 2 {
+		t.Error("Expected 2 keys in memory, got:", n)
+	}
+}
+
+func TestTieredCacheExpiry(t *testing.T) {
+	shared := &countingCache{MemoryCache: NewMemoryCache(100)}
+	shared.Set("key", Parsed{}, 5)
+
+	cache := NewTieredCache(shared, 10, time.Millisecond)
+	cache.Get("key")
+	time.Sleep(5 * time.Millisecond)
+	cache.Get("key")
+	if shared.gets != 2 {
+		t.Error("Expected the expired key to be fetched again, got:", shared.gets)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if n := cache.Expire(); n != 1 {
+		t.Error("Expected 1 expired key, got:", n)
+	}
+
+	// Results are not kept beyond their TTL
+	expired := Parsed{"ttl": time.Now().Add(-time.Second)}
+	cache = NewTieredCache(shared, 10, time.Minute)
+	cache.remember("expired", expired)
+	if n := cache.Len(); n != 0 {
+		t.Error("Expected the expired result not to be kept, got:", n)
+	}
+}
+
+func TestTieredCacheStale(t *testing.T) {
+	shared := NewMemoryCache(100)
+	shared.staleTime = 5 * time.Minute
+	shared.Set("key", Parsed{"foo": 23}, 5)
+	shared.m["key"]["ttl"] = time.Now().Add(-time.Minute)
+
+	// Expired keys retained by the shared cache are a
+	// miss, unless stale results are requested
+	cache := NewTieredCache(shared, 10, time.Minute)
+	if val, err := cache.Get("key"); err == nil {
+		t.Error("Expected a miss for the expired key, got:", val)
+	}
+	if n := cache.Len(); n != 0 {
+		t.Error("Expected the expired key not to be kept, got:", n)
+	}
+	if val, err := cache.GetStale("key"); err != nil || val["foo"] != 23 {
+		t.Error("Expected a stale hit for the expired key, got:", val, err)
+	}
+}
@@ -0,0 +1,51 @@
+package bird
+
+import (
+	"time"
+)
+
+// Layouts of the times shown by BIRD, depending on the
+// configured timeformat. Times are shown without date for
+// recent events and without time for old events.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"15:04:05.000",
+	"15:04:05",
+	"Jan02",
+	"2006",
+}
+
+// ParseTime parses a time shown by BIRD in the local time
+// zone. Times without a date are within the last day, dates
+// without a year within the last year.
+func ParseTime(value string, now time.Time) (time.Time, bool) {
+	now = now.In(time.Local)
+	for _, layout := range timeLayouts {
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err != nil {
+			continue
+		}
+		if t.Year() != 0 {
+			return t, true
+		}
+
+		if layout != "Jan02" { // Time of day
+			t = time.Date(now.Year(), now.Month(), now.Day(),
+				t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+			if t.After(now) {
+				t = t.AddDate(0, 0, -1)
+			}
+			return t, true
+		}
+
+		// Day of the year
+		t = time.Date(now.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		if t.After(now) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
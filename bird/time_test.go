@@ -0,0 +1,34 @@
+package bird
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2021, 3, 15, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2021-03-01 10:11:12", time.Date(2021, 3, 1, 10, 11, 12, 0, time.Local)},
+		{"2021-03-01 10:11:12.500", time.Date(2021, 3, 1, 10, 11, 12, 500000000, time.Local)},
+		{"2021-03-01", time.Date(2021, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"10:11:12", time.Date(2021, 3, 15, 10, 11, 12, 0, time.Local)},
+		{"13:00:00", time.Date(2021, 3, 14, 13, 0, 0, 0, time.Local)},
+		{"Feb02", time.Date(2021, 2, 2, 0, 0, 0, 0, time.Local)},
+		{"Dec24", time.Date(2020, 12, 24, 0, 0, 0, 0, time.Local)},
+		{"2019", time.Date(2019, 1, 1, 0, 0, 0, 0, time.Local)},
+	}
+	for _, test := range tests {
+		parsed, ok := ParseTime(test.value, now)
+		if !ok || !parsed.Equal(test.expected) {
+			t.Error("Expected", test.value, "to be", test.expected, "got:", parsed, ok)
+		}
+	}
+
+	if _, ok := ParseTime("Established", now); ok {
+		t.Error("Expected an error for an invalid time")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"sort"
+	"time"
 
 	"strings"
 
@@ -17,92 +19,162 @@ import (
 //go:generate versionize
 var VERSION = "2.0.0"
 
-func isModuleEnabled(module string, modulesEnabled []string) bool {
-	for _, enabled := range modulesEnabled {
-		if enabled == module {
-			return true
+// A route provided by a module
+type moduleRoute struct {
+	module string
+	path   string
+	handle httprouter.Handle
+}
+
+// All routes by module. See the config for a list
+// of the available modules.
+var moduleRoutes = []moduleRoute{
+	{"status", "/version", endpoints.Version(VERSION)},
+	{"status", "/status", endpoints.Endpoint(endpoints.Status)},
+	{"status_identity", "/status/identity", endpoints.Endpoint(endpoints.StatusIdentity)},
+	{"protocols", "/protocols", endpoints.Endpoint(endpoints.Protocols)},
+	{"protocols_bgp", "/protocols/bgp", endpoints.Endpoint(endpoints.Bgp)},
+	{"protocols_bgp_history", "/protocols/bgp/:protocol/:arg", endpoints.Endpoint(endpoints.BgpHistory)},
+	{"protocols_short", "/protocols/short", endpoints.Endpoint(endpoints.ProtocolsShort)},
+	{"protocols_states", "/protocols/states", endpoints.Endpoint(endpoints.ProtocolsStates)},
+	{"protocols_bgp_summary", "/protocols/bgp/:protocol", endpoints.Endpoint(endpoints.BgpSummary)},
+	{"protocols_bgp_as", "/protocols/bgp/:protocol/:arg", endpoints.Endpoint(endpoints.BgpNeighborAS)},
+	{"protocols_ospf_lsadb", "/protocols/ospf/lsadb", endpoints.Endpoint(endpoints.OspfLsadb)},
+	{"protocols_rip", "/protocols/rip", endpoints.Endpoint(endpoints.Rip)},
+	{"interfaces", "/interfaces", endpoints.Endpoint(endpoints.Interfaces)},
+	{"symbols", "/symbols", endpoints.Endpoint(endpoints.Symbols)},
+	{"symbols_tables", "/symbols/tables", endpoints.Endpoint(endpoints.SymbolTables)},
+	{"symbols_protocols", "/symbols/protocols", endpoints.Endpoint(endpoints.SymbolProtocols)},
+	{"routes_protocol", "/routes/protocol/:protocol", endpoints.Endpoint(endpoints.ProtoRoutes)},
+	{"routes_protocol_all", "/routes/protocol/:protocol/all", endpoints.Endpoint(endpoints.ProtoRoutesAll)},
+	{"routes_peer", "/routes/peer/:peer", endpoints.Endpoint(endpoints.PeerRoutes)},
+	{"routes_static", "/routes/static/:protocol", endpoints.Endpoint(endpoints.StaticRoutes)},
+	{"routes_table", "/routes/table", endpoints.Endpoint(endpoints.TableRoutes)},
+	{"routes_table", "/routes/table/:table", endpoints.Endpoint(endpoints.TableRoutes)},
+	{"routes_table_filtered", "/routes/table/:table/filtered", endpoints.Endpoint(endpoints.TableRoutesFiltered)},
+	{"routes_table_memory", "/routes/table/:table/memory", endpoints.Endpoint(endpoints.TableMemory)},
+	{"routes_table_peer", "/routes/table/:table/peer/:peer", endpoints.Endpoint(endpoints.TableAndPeerRoutes)},
+	{"routes_table_export", "/routes/table/:table/export/:protocol", endpoints.Endpoint(endpoints.TableExportRoutes)},
+	{"routes_table_since", "/routes/table/:table/since", endpoints.Endpoint(endpoints.TableRoutesSince)},
+	{"routes_table_tree", "/routes/table/:table/tree", endpoints.Endpoint(endpoints.TableRoutesTree)},
+	{"routes_table_origin", "/routes/table/:table/origin/:as", endpoints.Endpoint(endpoints.TableAndOriginRoutes)},
+	{"routes_table_interfaces", "/routes/table/:table/interfaces", endpoints.Endpoint(endpoints.TableRoutesInterfaces)},
+	{"routes_count_protocol", "/routes/count/protocol/:protocol", endpoints.Endpoint(endpoints.ProtoCount)},
+	{"routes_count_table", "/routes/count/table", endpoints.Endpoint(endpoints.TableCount)},
+	{"routes_count_table", "/routes/count/table/:table", endpoints.Endpoint(endpoints.TableCount)},
+	{"routes_count_primary", "/routes/count/primary/:protocol", endpoints.Endpoint(endpoints.ProtoPrimaryCount)},
+	{"routes_count_primary_table", "/routes/count/table/:table/primary", endpoints.Endpoint(endpoints.TablePrimaryCount)},
+	{"routes_count_table_peer", "/routes/count/table/:table/peer/:peer", endpoints.Endpoint(endpoints.TableAndPeerRoutesCount)},
+	{"routes_filtered", "/routes/filtered/:protocol", endpoints.Endpoint(endpoints.RoutesFiltered)},
+	{"routes_export", "/routes/export/:protocol", endpoints.Endpoint(endpoints.RoutesExport)},
+	{"routes_noexport", "/routes/noexport/:protocol", endpoints.Endpoint(endpoints.RoutesNoExport)},
+	{"routes_prefixed", "/routes/prefix", endpoints.Endpoint(endpoints.RoutesPrefixed)},
+	{"route_net", "/route/net/:net", endpoints.Endpoint(endpoints.RouteNet)},
+	{"route_net", "/route/net/:net/table/:table", endpoints.Endpoint(endpoints.RouteNetTable)},
+	{"route_for", "/route/for/:addr", endpoints.Endpoint(endpoints.RouteFor)},
+	{"route_net_tables", "/route/net/:net/tables", endpoints.Endpoint(endpoints.RouteNetTables)},
+	{"route_net_exports", "/route/net/:net/exports", endpoints.Endpoint(endpoints.RouteNetExports)},
+	{"route_net_peer", "/route/net/:net/peer/:peer", endpoints.Endpoint(endpoints.RouteNetPeer)},
+	{"route_net_mask", "/route/net/:net/mask/:mask", endpoints.Endpoint(endpoints.RouteNetMask)},
+	{"route_net_mask", "/route/net/:net/mask/:mask/table/:table", endpoints.Endpoint(endpoints.RouteNetMaskTable)},
+	{"routes_pipe_filtered_count", "/routes/pipe/filtered/count", endpoints.Endpoint(endpoints.PipeRoutesFilteredCount)},
+	{"routes_pipe_filtered", "/routes/pipe/filtered", endpoints.Endpoint(endpoints.PipeRoutesFiltered)},
+	{"raw", "/raw", endpoints.Endpoint(endpoints.Raw)},
+	{"ws_protocols", "/ws/protocols", endpoints.WsProtocols},
+	{"metrics", "/metrics", endpoints.Metrics},
+	{"config_bird", "/config/bird", endpoints.BirdConfig},
+	{"debug", "/debug/pprof/*profile", endpoints.Pprof},
+	{"debug", "/debug/vars", endpoints.Expvar},
+}
+
+// Modules sharing a path with a parameter, as httprouter
+// does not allow static segments next to parameters, e.g.
+// /protocols/bgp/summary next to /protocols/bgp/:protocol/history.
+// A module serves the request if its match returns true,
+// with the parameters returned by the match.
+var moduleMatches = map[string]func(httprouter.Params) (httprouter.Params, bool){
+	"protocols_bgp_summary": func(ps httprouter.Params) (httprouter.Params, bool) {
+		return ps, ps.ByName("protocol") == "summary"
+	},
+	"protocols_bgp_history": func(ps httprouter.Params) (httprouter.Params, bool) {
+		return ps, ps.ByName("arg") == "history"
+	},
+	"protocols_bgp_as": func(ps httprouter.Params) (httprouter.Params, bool) {
+		params := httprouter.Params{{Key: "as", Value: ps.ByName("arg")}}
+		return params, ps.ByName("protocol") == "as"
+	},
+}
+
+// Dispatch a request to the first module sharing the path,
+// which matches the parameters. Disabled modules answer
+// the request with an error, if none matches it is not found.
+func dispatchModules(routes []moduleRoute, enabled func(string) bool, status int) httprouter.Handle {
+	handles := make([]httprouter.Handle, len(routes))
+	for i, route := range routes {
+		if enabled(route.module) {
+			handles[i] = endpoints.WithModule(route.module, route.handle)
+		} else {
+			handles[i] = moduleDisabled(route.module, status)
 		}
 	}
 
-	return false
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		for i, route := range routes {
+			if params, ok := moduleMatches[route.module](ps); ok {
+				handles[i](w, r, params)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}
+}
+
+// Check if the module provides any routes
+func isModuleKnown(module string) bool {
+	for _, route := range moduleRoutes {
+		if route.module == module {
+			return true
+		}
+	}
+	return module == "management"
 }
 
 func makeRouter(config endpoints.ServerConfig) *httprouter.Router {
 	whitelist := config.ModulesEnabled
 
 	r := httprouter.New()
-	if isModuleEnabled("status", whitelist) {
-		r.GET("/version", endpoints.Version(VERSION))
-		r.GET("/status", endpoints.Endpoint(endpoints.Status))
-	}
-	if isModuleEnabled("protocols", whitelist) {
-		r.GET("/protocols", endpoints.Endpoint(endpoints.Protocols))
-	}
-	if isModuleEnabled("protocols_bgp", whitelist) {
-		r.GET("/protocols/bgp", endpoints.Endpoint(endpoints.Bgp))
-	}
-	if isModuleEnabled("protocols_short", whitelist) {
-		r.GET("/protocols/short", endpoints.Endpoint(endpoints.ProtocolsShort))
-	}
-	if isModuleEnabled("symbols", whitelist) {
-		r.GET("/symbols", endpoints.Endpoint(endpoints.Symbols))
-	}
-	if isModuleEnabled("symbols_tables", whitelist) {
-		r.GET("/symbols/tables", endpoints.Endpoint(endpoints.SymbolTables))
+	if config.RedirectTrailingSlash != nil {
+		r.RedirectTrailingSlash = *config.RedirectTrailingSlash
 	}
-	if isModuleEnabled("symbols_protocols", whitelist) {
-		r.GET("/symbols/protocols", endpoints.Endpoint(endpoints.SymbolProtocols))
+	if config.RedirectFixedPath != nil {
+		r.RedirectFixedPath = *config.RedirectFixedPath
 	}
-	if isModuleEnabled("routes_protocol", whitelist) {
-		r.GET("/routes/protocol/:protocol", endpoints.Endpoint(endpoints.ProtoRoutes))
-	}
-	if isModuleEnabled("routes_peer", whitelist) {
-		r.GET("/routes/peer/:peer", endpoints.Endpoint(endpoints.PeerRoutes))
-	}
-	if isModuleEnabled("routes_table", whitelist) {
-		r.GET("/routes/table/:table", endpoints.Endpoint(endpoints.TableRoutes))
-	}
-	if isModuleEnabled("routes_table_filtered", whitelist) {
-		r.GET("/routes/table/:table/filtered", endpoints.Endpoint(endpoints.TableRoutesFiltered))
-	}
-	if isModuleEnabled("routes_table_peer", whitelist) {
-		r.GET("/routes/table/:table/peer/:peer", endpoints.Endpoint(endpoints.TableAndPeerRoutes))
-	}
-	if isModuleEnabled("routes_count_protocol", whitelist) {
-		r.GET("/routes/count/protocol/:protocol", endpoints.Endpoint(endpoints.ProtoCount))
-	}
-	if isModuleEnabled("routes_count_table", whitelist) {
-		r.GET("/routes/count/table/:table", endpoints.Endpoint(endpoints.TableCount))
-	}
-	if isModuleEnabled("routes_count_primary", whitelist) {
-		r.GET("/routes/count/primary/:protocol", endpoints.Endpoint(endpoints.ProtoPrimaryCount))
-	}
-	if isModuleEnabled("routes_filtered", whitelist) {
-		r.GET("/routes/filtered/:protocol", endpoints.Endpoint(endpoints.RoutesFiltered))
-	}
-	if isModuleEnabled("routes_export", whitelist) {
-		r.GET("/routes/export/:protocol", endpoints.Endpoint(endpoints.RoutesExport))
-	}
-	if isModuleEnabled("routes_noexport", whitelist) {
-		r.GET("/routes/noexport/:protocol", endpoints.Endpoint(endpoints.RoutesNoExport))
-	}
-	if isModuleEnabled("routes_prefixed", whitelist) {
-		r.GET("/routes/prefix", endpoints.Endpoint(endpoints.RoutesPrefixed))
-	}
-	if isModuleEnabled("route_net", whitelist) {
-		r.GET("/route/net/:net", endpoints.Endpoint(endpoints.RouteNet))
-		r.GET("/route/net/:net/table/:table", endpoints.Endpoint(endpoints.RouteNetTable))
+	enabled := func(module string) bool {
+		if module == "metrics" && metricsSink != "prometheus" {
+			return false
+		}
+		return endpoints.IsModuleEnabled(module, whitelist)
 	}
-	if isModuleEnabled("route_net_mask", whitelist) {
-		r.GET("/route/net/:net/mask/:mask", endpoints.Endpoint(endpoints.RouteNetMask))
-		r.GET("/route/net/:net/mask/:mask/table/:table", endpoints.Endpoint(endpoints.RouteNetMaskTable))
+	shared := map[string][]moduleRoute{}
+	for _, route := range moduleRoutes {
+		if _, ok := moduleMatches[route.module]; ok {
+			shared[route.path] = append(shared[route.path], route)
+			continue
+		}
+		if enabled(route.module) {
+			r.GET(route.path, endpoints.WithModule(route.module, route.handle))
+		}
 	}
-	if isModuleEnabled("routes_pipe_filtered_count", whitelist) {
-		r.GET("/routes/pipe/filtered/count", endpoints.Endpoint(endpoints.PipeRoutesFilteredCount))
+	for path, routes := range shared {
+		r.GET(path, dispatchModules(routes, enabled, moduleDisabledStatus(config)))
 	}
-	if isModuleEnabled("routes_pipe_filtered", whitelist) {
-		r.GET("/routes/pipe/filtered", endpoints.Endpoint(endpoints.PipeRoutesFiltered))
+	if enabled("management") {
+		r.POST("/config/modules/:module/:action",
+			endpoints.WithModule("management", setModuleEnabled))
+		r.POST("/config/maintenance/:action",
+			endpoints.WithModule("management", setMaintenance))
 	}
+	r.NotFound = disabledModulesHandler(config, enabled)
 
 	return r
 }
@@ -113,7 +185,13 @@ func PrintServiceInfo(conf *Config, birdConf bird.BirdConfig) {
 	// General Info
 	log.Println("Starting Birdwatcher")
 	log.Println("            Using:", birdConf.BirdCmd)
-	log.Println("           Listen:", birdConf.Listen)
+	for _, listener := range getListeners(birdConf, conf.Server) {
+		if listener.TLS {
+			log.Println("           Listen:", listener.Address, "(TLS)")
+		} else {
+			log.Println("           Listen:", listener.Address)
+		}
+	}
 	log.Println("        Cache TTL:", birdConf.CacheTtl)
 
 	// Endpoint Info
@@ -126,8 +204,14 @@ func PrintServiceInfo(conf *Config, birdConf bird.BirdConfig) {
 	if conf.Cache.UseRedis {
 		log.Println("    Caching backend: REDIS")
 		log.Println("       Using server:", conf.Cache.RedisServer)
+		if conf.Cache.MemoryTierKeys > 0 {
+			log.Println("        Memory tier:", conf.Cache.MemoryTierKeys, "keys")
+		}
 	} else {
 		log.Println("    Caching backend: MEMORY")
+		if conf.Cache.PersistFile != "" {
+			log.Println("         Persist to:", conf.Cache.PersistFile)
+		}
 	}
 
 	log.Println("   ModulesEnabled:")
@@ -136,6 +220,47 @@ func PrintServiceInfo(conf *Config, birdConf bird.BirdConfig) {
 	}
 }
 
+// Check birdc at startup. Depending on the config
+// a failed check is either fatal or a warning.
+func checkBirdc(birdConf bird.BirdConfig) {
+	if birdConf.StartupCheck == "off" {
+		return
+	}
+	err := bird.CheckBirdc(birdConf.StartupCheckStatus)
+	if err == nil {
+		return
+	}
+	if birdConf.StartupCheck == "fail" {
+		log.Fatal("Checking birdc failed: ", err)
+	}
+	log.Println("WARNING: Checking birdc failed, requests will fail:", err)
+}
+
+// Check the lists of addresses and networks in the config.
+// Invalid entries are skipped when checking the clients, so
+// they are reported at startup.
+func checkNetLists(conf *Config) {
+	lists := map[string][]string{
+		"server.allow_from":        conf.Server.AllowFrom,
+		"server.conn_limit_exempt": conf.Server.ConnLimitExempt,
+		"ratelimit.exempt_from":    conf.Ratelimit.ExemptFrom,
+	}
+	for module, allowed := range conf.Server.ModulesAllowFrom {
+		lists["server.modules_allow_from."+module] = allowed
+	}
+
+	names := make([]string, 0, len(lists))
+	for name := range lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := endpoints.ParseNetList(lists[name]); err != nil {
+			log.Println("WARNING: Ignoring entries of", name+":", err)
+		}
+	}
+}
+
 // MyLogger is our own log.Logger wrapper so we can customize it
 type MyLogger struct {
 	logger *log.Logger
@@ -156,12 +281,21 @@ func main() {
 
 	// Profiling
 	memoryProfile := flag.String("memprofile", "", "write memory profile to this file")
+	memoryProfileRetain := flag.Int("memprofile-retain", 0, "number of memory profiles to keep, 0 keeps all")
+	memoryProfileInterval := flag.Duration("memprofile-interval", 30*time.Second, "interval between memory profiles")
+	cpuProfile := flag.String("cpuprofile", "", "write cpu profile to this file")
+	cpuProfileDuration := flag.Duration("cpuprofile-duration", 30*time.Second, "duration of the cpu profile")
 
 	flag.Parse()
 
 	// Start memory profiling if filename is present
 	if *memoryProfile != "" {
-		go startMemoryProfile(*memoryProfile)
+		go startMemoryProfile(*memoryProfile, *memoryProfileRetain, *memoryProfileInterval)
+	}
+
+	// Start cpu profiling if filename is present
+	if *cpuProfile != "" {
+		go startCPUProfile(*cpuProfile, *cpuProfileDuration)
 	}
 
 	bird.WorkerPoolSize = *workerPoolSize
@@ -170,11 +304,12 @@ func main() {
 	if err != nil {
 		log.Fatal("Loading birdwatcher configuration failed:", err)
 	}
-
-	if conf.Server.EnableTLS {
-		if len(conf.Server.Crt) == 0 || len(conf.Server.Key) == 0 {
-			log.Fatalln("You have enabled TLS support. Please specify 'crt' and 'key' in birdwatcher config file.")
+	if conf.Server.ModulesFile != "" {
+		modules, err := LoadModulesFile(conf.Server.ModulesFile)
+		if err != nil {
+			log.Fatal("Loading modules file failed: ", err)
 		}
+		conf.Server.ModulesEnabled = modules
 	}
 
 	endpoints.VERSION = VERSION
@@ -187,37 +322,97 @@ func main() {
 		bird.IPVersion = "6"
 	}
 
+	listeners := getListeners(birdConf, conf.Server)
+	for _, listener := range listeners {
+		if listener.TLS && (len(conf.Server.Crt) == 0 || len(conf.Server.Key) == 0) {
+			log.Fatalln("You have enabled TLS support. Please specify 'crt' and 'key' in birdwatcher config file.")
+		}
+	}
+
 	PrintServiceInfo(conf, birdConf)
+	checkNetLists(conf)
 
 	// Configuration
 	bird.ClientConf = birdConf
+	checkBirdc(birdConf)
 	bird.StatusConf = conf.Status
 	bird.RateLimitConf.Lock()
 	bird.RateLimitConf.Conf = conf.Ratelimit
 	bird.RateLimitConf.Unlock()
+	endpoints.SetRateLimitExemptFrom(conf.Ratelimit.ExemptFrom)
 	bird.ParserConf = conf.Parser
 	bird.CacheConf = conf.Cache
 	bird.InitializeCache()
+	bird.LoadCache()
 
 	endpoints.Conf = conf.Server
+	endpoints.RawConf = conf.Raw
+	endpoints.WebSocketConf = conf.WebSocket
+	endpoints.GrpcConf = conf.Grpc
+	endpoints.HistoryConf = conf.History
+	endpoints.NetTablesConf = conf.NetTables
+	endpoints.LabelsConf = conf.Labels
+	endpoints.ResolveConf = conf.Resolve
+	endpoints.RpkiConf = conf.Rpki
+
+	// The sink decides if the metrics endpoint is served
+	if endpoints.IsModuleEnabled("metrics", conf.Server.ModulesEnabled) {
+		GuardMetricsLabels(conf.Metrics)
+		if err := StartMetricsSink(conf.Metrics); err != nil {
+			log.Fatal("Could not start metrics sink: ", err)
+		}
+	}
 
 	// Make server
-	r := makeRouter(conf.Server)
+	liveRouter = NewLiveRouter(conf.Server)
+	r := liveRouter
+	if conf.Server.ModulesFile != "" {
+		go ReloadModulesFile(conf.Server.ModulesFile, liveRouter)
+	}
 
 	// Set up our own custom log.Logger without a prefix
 	myquerylog := log.New(os.Stdout, "", 0)
 	// Disable timestamps, as they are contained in the query log
 	myquerylog.SetFlags(myquerylog.Flags() &^ (log.Ldate | log.Ltime))
-	// mylogger := &MyLogger{myquerylog}
 
-	go Housekeeping(conf.Housekeeping, !(bird.CacheConf.UseRedis)) // expire caches only for MemoryCache
+	// expire caches only for MemoryCache and the memory tier of redis
+	expireCaches := !bird.CacheConf.UseRedis || bird.CacheConf.MemoryTierKeys > 0
+	go Housekeeping(conf.Housekeeping, expireCaches)
 
-	if conf.Server.EnableTLS {
-		if len(conf.Server.Crt) == 0 || len(conf.Server.Key) == 0 {
-			log.Fatalln("You have enabled TLS support but not specified both a .crt and a .key file in the config.")
-		}
-		log.Fatal(http.ListenAndServeTLS(birdConf.Listen, conf.Server.Crt, conf.Server.Key, r))
-	} else {
-		log.Fatal(http.ListenAndServe(birdConf.Listen, r))
+	if endpoints.IsModuleEnabled("metrics", conf.Server.ModulesEnabled) {
+		go ProbeBird(conf.Metrics)
+		go CountTableRoutes(conf.Metrics)
+	}
+
+	if endpoints.IsModuleEnabled("protocols_bgp_history", conf.Server.ModulesEnabled) {
+		go endpoints.CollectPrefixHistory()
+	}
+
+	// Servers stopped on shutdown besides the HTTP servers
+	stops := []func(){}
+	if conf.Grpc.Enabled {
+		grpcServer := endpoints.NewGrpcServer(conf.Server.ModulesEnabled)
+		go func() {
+			log.Println("Serving gRPC on", conf.Grpc.Listen)
+			if err := endpoints.ServeGrpc(grpcServer); err != nil {
+				log.Fatal("Could not serve gRPC: ", err)
+			}
+		}()
+		stops = append(stops, grpcServer.GracefulStop)
+	}
+
+	if conf.Server.MaintenanceFile != "" {
+		go endpoints.WatchMaintenanceFile()
+	}
+
+	if conf.Cache.PersistFile != "" && !conf.Cache.UseRedis {
+		go PersistCache(conf.Cache)
+	}
+
+	Serve(listeners, conf.Server, queryLogHandler(myquerylog, conf.Logging, r), stops...)
+
+	// Keep the cache for the next start
+	if err := bird.SaveCache(); err != nil {
+		log.Println("Could not save cache snapshot:", err)
 	}
 }
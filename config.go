@@ -16,6 +16,15 @@ import (
 
 type Config struct {
 	Server endpoints.ServerConfig
+	Raw    endpoints.RawConfig
+
+	WebSocket endpoints.WebSocketConfig
+	Grpc      endpoints.GrpcConfig
+	History   endpoints.HistoryConfig
+	NetTables endpoints.NetTablesConfig `toml:"net_tables"`
+	Labels    endpoints.LabelsConfig
+	Resolve   endpoints.ResolveConfig
+	Rpki      endpoints.RpkiConfig
 
 	Ratelimit    bird.RateLimitConfig
 	Status       bird.StatusConfig
@@ -24,6 +33,8 @@ type Config struct {
 	Parser       bird.ParserConfig
 	Cache        bird.CacheConfig
 	Housekeeping HousekeepingConfig
+	Metrics      MetricsConfig
+	Logging      LoggingConfig
 }
 
 // Try to load configfiles as specified in the files
@@ -58,9 +69,27 @@ func LoadConfigs(configFiles []string) (*Config, error) {
 		confError = fmt.Errorf("Could not load any config file")
 	}
 
+	config.Server.ModulesEnabled = uniqueModules(config.Server.ModulesEnabled)
+
 	return config, confError
 }
 
+// Remove duplicate entries from the list of enabled
+// modules, keeping the first occurrence.
+func uniqueModules(modules []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, module := range modules {
+		if seen[module] {
+			log.Println("Warning: module", module, "is enabled more than once")
+			continue
+		}
+		seen[module] = true
+		unique = append(unique, module)
+	}
+	return unique
+}
+
 func ConfigOptions(filename string) []string {
 	return []string{
 		strings.Join([]string{"/", filename}, ""),
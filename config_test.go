@@ -1,7 +1,12 @@
 package main
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/alice-lg/birdwatcher/endpoints"
 )
 
 func TestLoadConfigs(t *testing.T) {
@@ -15,3 +20,93 @@ func TestLoadConfigs(t *testing.T) {
 	t.Log(res)
 	t.Log(err)
 }
+
+func TestUniqueModules(t *testing.T) {
+	modules := uniqueModules([]string{"status", "routes_table", "status"})
+	if len(modules) != 2 || modules[0] != "status" || modules[1] != "routes_table" {
+		t.Error("Expected duplicate module to be removed, got:", modules)
+	}
+}
+
+func TestMakeRouterDuplicateModule(t *testing.T) {
+	defer func() {
+		if err := recover(); err != nil {
+			t.Error("Duplicate module must not panic:", err)
+		}
+	}()
+	makeRouter(endpoints.ServerConfig{
+		ModulesEnabled: []string{"status", "management", "status", "management"},
+	})
+}
+
+func TestMakeRouterRedirects(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		conf   endpoints.ServerConfig
+		path   string
+		status int
+	}{
+		{endpoints.ServerConfig{}, "/version/", http.StatusMovedPermanently},
+		{endpoints.ServerConfig{}, "/VERSION", http.StatusMovedPermanently},
+		{endpoints.ServerConfig{RedirectTrailingSlash: &disabled}, "/version/", http.StatusNotFound},
+		{endpoints.ServerConfig{RedirectFixedPath: &disabled}, "/VERSION", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		test.conf.ModulesEnabled = []string{"status"}
+		r := makeRouter(test.conf)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+		if w.Code != test.status {
+			t.Error("Expected", test.status, "for", test.path, "got:", w.Code)
+		}
+	}
+}
+
+func TestMakeRouterDisabledModule(t *testing.T) {
+	tests := []struct {
+		conf   endpoints.ServerConfig
+		method string
+		path   string
+		status int
+	}{
+		{endpoints.ServerConfig{}, "GET", "/version", http.StatusOK},
+		{endpoints.ServerConfig{}, "GET", "/routes/table/master4", http.StatusForbidden},
+		{endpoints.ServerConfig{}, "POST", "/config/maintenance/enable", http.StatusForbidden},
+		{endpoints.ServerConfig{ModuleDisabledStatus: 501}, "GET", "/protocols", http.StatusNotImplemented},
+		{endpoints.ServerConfig{}, "GET", "/unknown", http.StatusNotFound},
+		{endpoints.ServerConfig{}, "GET", "/protocols/bgp/summary", http.StatusForbidden},
+		{endpoints.ServerConfig{}, "GET", "/protocols/bgp/R1", http.StatusNotFound},
+		{endpoints.ServerConfig{}, "GET", "/protocols/bgp/R1/history", http.StatusForbidden},
+		{endpoints.ServerConfig{}, "GET", "/protocols/bgp/as/65000", http.StatusForbidden},
+		{endpoints.ServerConfig{}, "GET", "/protocols/bgp/R1/unknown", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		test.conf.ModulesEnabled = []string{"status"}
+		r := makeRouter(test.conf)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(test.method, test.path, nil))
+		if w.Code != test.status {
+			t.Error("Expected", test.status, "for", test.path, "got:", w.Code)
+		}
+	}
+}
+
+func TestMakeRouterSharedPath(t *testing.T) {
+	r := makeRouter(endpoints.ServerConfig{
+		ModulesEnabled: []string{"protocols_bgp_history"},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/protocols/bgp/R1/history", nil))
+	if !strings.Contains(w.Body.String(), "no history for protocol: R1") {
+		t.Error("Expected the history of R1 to be dispatched, got:", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/protocols/bgp/as/65000", nil))
+	if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "protocols_bgp_as") {
+		t.Error("Expected protocols_bgp_as to be disabled, got:", w.Code, w.Body.String())
+	}
+}
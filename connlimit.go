@@ -0,0 +1,121 @@
+package main
+
+// Limit of the concurrent connections per client
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/alice-lg/birdwatcher/endpoints"
+)
+
+// connLimiter tracks the open connections per client IP.
+// Connections exceeding the limit are admitted by the
+// server, but their requests are rejected and they are
+// closed after the response.
+type connLimiter struct {
+	sync.Mutex
+	limit  int
+	exempt []*net.IPNet
+
+	open     map[string]int
+	rejected map[string]bool
+}
+
+func newConnLimiter(limit int, exempt []string) *connLimiter {
+	l := &connLimiter{
+		limit:    limit,
+		open:     map[string]int{},
+		rejected: map[string]bool{},
+	}
+	l.exempt, _ = endpoints.ParseNetList(exempt)
+	return l
+}
+
+// Get the IP of a remote address
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+func (l *connLimiter) isExempt(ip string) bool {
+	clientIP := net.ParseIP(ip)
+	if clientIP == nil {
+		return false
+	}
+	return endpoints.NetListContains(l.exempt, clientIP)
+}
+
+// ConnState counts the connections of the clients and is
+// used as the ConnState hook of the http.Server.
+func (l *connLimiter) ConnState(conn net.Conn, state http.ConnState) {
+	addr := conn.RemoteAddr().String()
+	ip := remoteIP(addr)
+
+	l.Lock()
+	defer l.Unlock()
+
+	switch state {
+	case http.StateNew:
+		if l.isExempt(ip) {
+			return
+		}
+		if l.open[ip] >= l.limit {
+			l.rejected[addr] = true
+			return
+		}
+		l.open[ip]++
+	case http.StateClosed, http.StateHijacked:
+		if l.rejected[addr] {
+			delete(l.rejected, addr)
+			return
+		}
+		if l.isExempt(ip) {
+			return
+		}
+		l.open[ip]--
+		if l.open[ip] <= 0 {
+			delete(l.open, ip)
+		}
+	}
+}
+
+func (l *connLimiter) isRejected(addr string) bool {
+	l.Lock()
+	defer l.Unlock()
+	return l.rejected[addr]
+}
+
+// Handler rejects the requests of connections
+// exceeding the limit with 429 Too Many Requests.
+func (l *connLimiter) Handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.isRejected(r.RemoteAddr) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Connection", "close")
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+		js, _ := json.Marshal(map[string]string{
+			"error": "too many concurrent connections",
+		})
+		w.Write(js)
+	})
+}
+
+// Get the connection limiter of the server, which is
+// nil if connections are not limited.
+func serverConnLimiter(serverConf endpoints.ServerConfig) *connLimiter {
+	if serverConf.ConnLimit <= 0 {
+		return nil
+	}
+	return newConnLimiter(serverConf.ConnLimit, serverConf.ConnLimitExempt)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConnLimiter(t *testing.T) {
+	limiter := newConnLimiter(2, nil)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	srv := httptest.NewUnstartedServer(limiter.Handler(handler))
+	srv.Config.ConnState = limiter.ConnState
+	srv.Start()
+	defer srv.Close()
+
+	// Each client uses its own connection
+	get := func() *http.Response {
+		client := &http.Client{Transport: &http.Transport{}}
+		res, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res
+	}
+
+	if res := get(); res.StatusCode != http.StatusOK {
+		t.Error("Expected first connection to be accepted, got:", res.StatusCode)
+	}
+	if res := get(); res.StatusCode != http.StatusOK {
+		t.Error("Expected second connection to be accepted, got:", res.StatusCode)
+	}
+	if res := get(); res.StatusCode != http.StatusTooManyRequests {
+		t.Error("Expected third connection to be rejected, got:", res.StatusCode)
+	}
+}
+
+func TestConnLimiterClosed(t *testing.T) {
+	limiter := newConnLimiter(1, []string{"192.0.2.1"})
+	client := &net.TCPAddr{IP: net.ParseIP("198.51.100.1"), Port: 1234}
+	conn := &fakeConn{remote: client}
+
+	limiter.ConnState(conn, http.StateNew)
+	limiter.ConnState(conn, http.StateClosed)
+	limiter.ConnState(conn, http.StateNew)
+	if limiter.isRejected(client.String()) {
+		t.Error("Expected closed connections not to be counted")
+	}
+
+	exempt := &fakeConn{remote: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1}}
+	for i := 0; i < 3; i++ {
+		limiter.ConnState(exempt, http.StateNew)
+	}
+	if limiter.isRejected(exempt.remote.String()) {
+		t.Error("Expected exempt client not to be limited")
+	}
+}
+
+type fakeConn struct {
+	net.Conn
+	remote net.Addr
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr { return c.remote }
@@ -0,0 +1,60 @@
+package main
+
+// Responses for the routes of disabled modules
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alice-lg/birdwatcher/endpoints"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Get the status requests for disabled modules are
+// answered with from the config, defaults to 403.
+func moduleDisabledStatus(config endpoints.ServerConfig) int {
+	status := config.ModuleDisabledStatus
+	if status >= 400 && status < 600 {
+		return status
+	}
+	return http.StatusForbidden
+}
+
+// Answer a request for a disabled module
+func moduleDisabled(module string, status int) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		writeManagementResponse(w, status, map[string]string{
+			"error":  fmt.Sprintf("module %s is disabled on this instance", module),
+			"module": module,
+		})
+	}
+}
+
+// Make the handler for requests not matching an enabled
+// module. Requests for the routes of known but disabled
+// modules are answered with an error naming the module,
+// all other requests are not found. Modules sharing a path
+// are answered by the dispatch of the path.
+func disabledModulesHandler(config endpoints.ServerConfig, enabled func(string) bool) http.Handler {
+	status := moduleDisabledStatus(config)
+
+	r := httprouter.New()
+	r.RedirectTrailingSlash = false
+	r.RedirectFixedPath = false
+	r.HandleMethodNotAllowed = false
+	for _, route := range moduleRoutes {
+		if _, ok := moduleMatches[route.module]; ok {
+			continue
+		}
+		if !enabled(route.module) {
+			r.GET(route.path, moduleDisabled(route.module, status))
+		}
+	}
+	if !enabled("management") {
+		r.POST("/config/modules/:module/:action", moduleDisabled("management", status))
+		r.POST("/config/maintenance/:action", moduleDisabled("management", status))
+	}
+
+	return r
+}
@@ -0,0 +1,113 @@
+package endpoints
+
+// Restrict the tables and protocols served
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+func isNameAllowed(allowed []string, name string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if a table may be queried. All tables
+// are allowed if no allow-list is configured.
+func isTableAllowed(table string) bool {
+	return isNameAllowed(Conf.AllowTables, table)
+}
+
+// Check if a protocol may be queried. All protocols
+// are allowed if no allow-list is configured.
+func isProtocolAllowed(protocol string) bool {
+	return isNameAllowed(Conf.AllowProtocols, protocol)
+}
+
+// Check the tables and protocols requested by the params
+// of the route or the query against the allow-lists.
+func checkAllowedNames(r *http.Request, ps httprouter.Params) error {
+	qs := r.URL.Query()
+
+	tables := qs["table"]
+	if table := ps.ByName("table"); table != "" {
+		tables = append(tables, table)
+	}
+	for _, table := range tables {
+		if !isTableAllowed(table) {
+			return fmt.Errorf("table is not allowed: %s", table)
+		}
+	}
+
+	protocols := qs["pipe"]
+	if protocol := ps.ByName("protocol"); protocol != "" {
+		protocols = append(protocols, protocol)
+	}
+	for _, protocol := range protocols {
+		if !isProtocolAllowed(protocol) {
+			return fmt.Errorf("protocol is not allowed: %s", protocol)
+		}
+	}
+
+	return nil
+}
+
+// Validate a table in a raw command, which
+// must be allowed to be queried.
+func validateAllowedTable(value string) (string, error) {
+	table, err := ValidateProtocolParam(value)
+	if err != nil {
+		return "", err
+	}
+	if !isTableAllowed(table) {
+		return "", fmt.Errorf("table is not allowed: %s", table)
+	}
+	return table, nil
+}
+
+// Validate a protocol in a raw command, which
+// must be allowed to be queried.
+func validateAllowedProtocol(value string) (string, error) {
+	protocol, err := ValidateProtocolParam(value)
+	if err != nil {
+		return "", err
+	}
+	if !isProtocolAllowed(protocol) {
+		return "", fmt.Errorf("protocol is not allowed: %s", protocol)
+	}
+	return protocol, nil
+}
+
+// Remove the tables which are not allowed from the
+// result of a lookup in all tables. The result is
+// copied, as it might be cached.
+func selectAllowedTables(res bird.Parsed) bird.Parsed {
+	tables, ok := parsedMap(res["tables"])
+	if !ok || len(Conf.AllowTables) == 0 {
+		return res
+	}
+
+	allowed := bird.Parsed{}
+	for table, routes := range tables {
+		if isTableAllowed(table) {
+			allowed[table] = routes
+		}
+	}
+
+	selected := bird.Parsed{}
+	for k, v := range res {
+		selected[k] = v
+	}
+	selected["tables"] = allowed
+	return selected
+}
@@ -0,0 +1,74 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestCheckAllowedNames(t *testing.T) {
+	Conf.AllowTables = []string{"tenant1"}
+	Conf.AllowProtocols = []string{"R192_1"}
+	defer func() {
+		Conf.AllowTables = nil
+		Conf.AllowProtocols = nil
+	}()
+
+	tests := []struct {
+		url     string
+		params  httprouter.Params
+		allowed bool
+	}{
+		{"/routes/table/tenant1", httprouter.Params{{Key: "table", Value: "tenant1"}}, true},
+		{"/routes/table/tenant2", httprouter.Params{{Key: "table", Value: "tenant2"}}, false},
+		{"/routes/protocol/R192_1", httprouter.Params{{Key: "protocol", Value: "R192_1"}}, true},
+		{"/routes/protocol/R192_2", httprouter.Params{{Key: "protocol", Value: "R192_2"}}, false},
+		{"/routes/pipe/filtered?table=tenant1&pipe=R192_1", nil, true},
+		{"/routes/pipe/filtered?table=master&pipe=R192_1", nil, false},
+		{"/routes/pipe/filtered?table=tenant1&pipe=P1", nil, false},
+		{"/status", nil, true},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", test.url, nil)
+		err := checkAllowedNames(r, test.params)
+		if (err == nil) != test.allowed {
+			t.Error("Expected", test.url, "to be allowed:", test.allowed, "got:", err)
+		}
+	}
+
+	// Raw commands are restricted by their placeholders
+	RawConf.Commands = []string{"show route table '{table}' protocol {protocol}"}
+	defer func() { RawConf.Commands = nil }()
+	if !isRawCommandAllowed([]string{"show", "route", "table", "'tenant1'", "protocol", "R192_1"}) {
+		t.Error("Expected raw command to be allowed")
+	}
+	if isRawCommandAllowed([]string{"show", "route", "table", "'master'", "protocol", "R192_1"}) {
+		t.Error("Expected raw command for other table not to be allowed")
+	}
+}
+
+func TestSelectAllowedTables(t *testing.T) {
+	Conf.AllowTables = []string{"tenant1"}
+	defer func() { Conf.AllowTables = nil }()
+
+	res := bird.Parsed{
+		"tables": bird.Parsed{
+			"tenant1": []bird.Parsed{{"network": "10.0.0.0/8"}},
+			"tenant2": []bird.Parsed{{"network": "10.0.0.0/8"}},
+		},
+		"ttl": 5,
+	}
+	selected := selectAllowedTables(res)
+	tables := selected["tables"].(bird.Parsed)
+	if len(tables) != 1 || tables["tenant1"] == nil {
+		t.Error("Expected only the allowed table, got:", tables)
+	}
+	if len(res["tables"].(bird.Parsed)) != 2 {
+		t.Error("Expected the result not to be modified")
+	}
+	if selected["ttl"] != 5 {
+		t.Error("Expected ttl to be kept, got:", selected["ttl"])
+	}
+}
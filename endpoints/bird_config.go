@@ -0,0 +1,43 @@
+package endpoints
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+// BirdConfig serves the BIRD config file configured with
+// exposed_config as plain text. The config may contain
+// secrets, so the request must be authorized with an
+// admin token. Included files are not served.
+func BirdConfig(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if err := CheckAccess(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err := CheckAdminAuth(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	filename := bird.ClientConf.ExposedConfig
+	if filename == "" {
+		http.Error(w, "no config is exposed", http.StatusNotFound)
+		return
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		log.Println("Could not open exposed config:", err)
+		http.Error(w, "config is not available", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.Copy(w, f)
+}
@@ -0,0 +1,57 @@
+package endpoints
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestBirdConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "birdwatcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "bird.conf")
+	config := "router id 192.0.2.1;\n"
+	if err := ioutil.WriteFile(filename, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	Conf.AdminTokens = []string{"admin"}
+	defer func() { Conf = ServerConfig{} }()
+	defer func() { bird.ClientConf.ExposedConfig = "" }()
+
+	tests := []struct {
+		exposed string
+		auth    string
+		status  int
+	}{
+		{filename, "", http.StatusUnauthorized},
+		{filename, "Bearer wrong", http.StatusUnauthorized},
+		{"", "Bearer admin", http.StatusNotFound},
+		{filename, "Bearer admin", http.StatusOK},
+	}
+	for _, test := range tests {
+		bird.ClientConf.ExposedConfig = test.exposed
+		r := httptest.NewRequest("GET", "/config/bird", nil)
+		if test.auth != "" {
+			r.Header.Set("Authorization", test.auth)
+		}
+		w := httptest.NewRecorder()
+		BirdConfig(w, r, nil)
+
+		if w.Code != test.status {
+			t.Error("Expected status", test.status, "got:", w.Code, test)
+		}
+		if w.Code == http.StatusOK && w.Body.String() != config {
+			t.Error("Unexpected config:", w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package endpoints
+
+// Buffering of responses
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Get the maximum size of a buffered response
+// from the config, defaults to 1 MiB.
+func responseBufferSize() int {
+	if Conf.ResponseBuffer > 0 {
+		return Conf.ResponseBuffer
+	}
+	return 1 << 20
+}
+
+// responseBuffer buffers a response up to a limit, so small
+// responses are sent with Content-Length and ETag. Larger
+// responses are streamed to the client. After a failed
+// write, e.g. as the client disconnected, nothing is
+// written anymore.
+type responseBuffer struct {
+	w         http.ResponseWriter
+	buf       bytes.Buffer
+	limit     int
+	streaming bool
+	err       error
+}
+
+func newResponseBuffer(w http.ResponseWriter, limit int) *responseBuffer {
+	return &responseBuffer{w: w, limit: limit}
+}
+
+// Write to the client, keeping the first error
+func (b *responseBuffer) write(p []byte) (int, error) {
+	n, err := b.w.Write(p)
+	if err != nil {
+		b.err = err
+	}
+	return n, err
+}
+
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.streaming {
+		return b.write(p)
+	}
+	if b.buf.Len()+len(p) <= b.limit {
+		return b.buf.Write(p)
+	}
+
+	// The response exceeds the limit, the buffered
+	// part is written and the rest is streamed.
+	b.streaming = true
+	if _, err := b.write(b.buf.Bytes()); err != nil {
+		return 0, err
+	}
+	b.buf.Reset()
+	return b.write(p)
+}
+
+// Check if a write failed as the client disconnected
+func isClientDisconnect(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "broken pipe") ||
+		strings.Contains(msg, "connection reset by peer")
+}
+
+// Log a failed write. Disconnects of clients are
+// expected and only logged if configured.
+func logWriteError(r *http.Request, err error) {
+	if isClientDisconnect(err) {
+		if Conf.LogDisconnects {
+			log.Println("Client disconnected:", r.RemoteAddr, r.URL.Path, err)
+		}
+		return
+	}
+	log.Println("Could not write response:", r.RemoteAddr, r.URL.Path, err)
+}
+
+// Get the ETag of a response body
+func responseETag(body []byte) string {
+	h := fnv.New64a()
+	h.Write(body)
+	return fmt.Sprintf(`"%016x"`, h.Sum64())
+}
+
+// Check if the ETag is matched by the If-None-Match header
+func matchesETag(header string, etag string) bool {
+	for _, match := range strings.Split(header, ",") {
+		match = strings.TrimSpace(match)
+		if match == "*" || strings.TrimPrefix(match, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Finish the response. It must be called after all writes,
+// e.g. when the gzip writer is closed. A buffered response
+// is not sent again, if the client has it already.
+func (b *responseBuffer) finish(r *http.Request) {
+	if b.err != nil {
+		logWriteError(r, b.err)
+		return
+	}
+	if b.streaming {
+		if f, ok := b.w.(http.Flusher); ok {
+			f.Flush()
+		}
+		return
+	}
+
+	body := b.buf.Bytes()
+	etag := responseETag(body)
+	b.w.Header().Set("ETag", etag)
+	if matchesETag(r.Header.Get("If-None-Match"), etag) {
+		b.w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	b.w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if _, err := b.write(body); err != nil {
+		logWriteError(r, err)
+	}
+}
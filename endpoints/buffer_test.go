@@ -0,0 +1,183 @@
+package endpoints
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestResponseBuffer(t *testing.T) {
+	w := httptest.NewRecorder()
+	out := newResponseBuffer(w, 16)
+	out.Write([]byte(`{"routes":`))
+	out.Write([]byte(`[]}`))
+	out.finish(httptest.NewRequest("GET", "/routes/table/master", nil))
+
+	if w.Header().Get("Content-Length") != "13" {
+		t.Error("Expected Content-Length 13, got:", w.Header().Get("Content-Length"))
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Error("Expected an ETag for a buffered response")
+	}
+	if w.Body.String() != `{"routes":[]}` {
+		t.Error("Unexpected body:", w.Body.String())
+	}
+
+	// The client has the response already
+	r := httptest.NewRequest("GET", "/routes/table/master", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	out = newResponseBuffer(w, 16)
+	out.Write([]byte(`{"routes":[]}`))
+	out.finish(r)
+	if w.Code != 304 || w.Body.Len() != 0 {
+		t.Error("Expected 304 without body, got:", w.Code, w.Body.String())
+	}
+}
+
+func TestResponseBufferStreaming(t *testing.T) {
+	body := bytes.Repeat([]byte("birdwatcher"), 10)
+
+	w := httptest.NewRecorder()
+	out := newResponseBuffer(w, 16)
+	for i := 0; i < len(body); i += 10 {
+		out.Write(body[i : i+10])
+	}
+	out.finish(httptest.NewRequest("GET", "/routes/table/master", nil))
+
+	if w.Header().Get("Content-Length") != "" || w.Header().Get("ETag") != "" {
+		t.Error("Expected no Content-Length and ETag for a streamed response")
+	}
+	if !bytes.Equal(w.Body.Bytes(), body) {
+		t.Error("Unexpected body:", w.Body.String())
+	}
+	if !w.Flushed {
+		t.Error("Expected the streamed response to be flushed")
+	}
+}
+
+func TestMatchesETag(t *testing.T) {
+	tests := []struct {
+		header string
+		match  bool
+	}{
+		{"", false},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"def", "abc"`, true},
+		{`"def"`, false},
+		{"*", true},
+	}
+	for _, test := range tests {
+		if matchesETag(test.header, `"abc"`) != test.match {
+			t.Error("Expected", test.header, "to match:", test.match)
+		}
+	}
+}
+
+// A client closing the connection after some bytes
+type closingWriter struct {
+	*httptest.ResponseRecorder
+	remaining int
+	writes    int
+}
+
+func (w *closingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if len(p) > w.remaining {
+		return 0, &net.OpError{Op: "write", Net: "tcp", Err: syscall.EPIPE}
+	}
+	w.remaining -= len(p)
+	return w.ResponseRecorder.Write(p)
+}
+
+func TestResponseBufferClientClosed(t *testing.T) {
+	w := &closingWriter{ResponseRecorder: httptest.NewRecorder(), remaining: 30}
+	out := newResponseBuffer(w, 16)
+	for i := 0; i < 10; i++ {
+		out.Write([]byte("birdwatcher"))
+	}
+	out.finish(httptest.NewRequest("GET", "/routes/table/master", nil))
+
+	if !isClientDisconnect(out.err) {
+		t.Error("Expected a disconnect, got:", out.err)
+	}
+	if w.writes != 3 {
+		t.Error("Expected no writes after the client closed, got:", w.writes)
+	}
+	if w.Flushed {
+		t.Error("Expected no flush after the client closed")
+	}
+}
+
+func TestEndpointClientClosedMidStream(t *testing.T) {
+	Conf.ResponseBuffer = 1024
+	defer func() { Conf = ServerConfig{} }()
+
+	routes := make([]bird.Parsed, 0, 100000)
+	for i := 0; i < cap(routes); i++ {
+		routes = append(routes, bird.Parsed{"network": fmt.Sprintf("10.%d.%d.0/24", i/256%256, i%256)})
+	}
+
+	done := make(chan struct{})
+	handle := Endpoint(func(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+		return bird.Parsed{"routes": routes}, false
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		handle(w, r, nil)
+	}))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprint(conn, "GET /routes/table/master HTTP/1.1\r\nHost: birdwatcher\r\n\r\n")
+	if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the handler to finish after the client closed")
+	}
+}
+
+func TestProtoRoutesStreaming(t *testing.T) {
+	defer helperBirdc("routes_bird1_ipv4.sample")()
+	Conf.ResponseBuffer = 1024
+	defer func() { Conf = ServerConfig{} }()
+
+	tests := []struct {
+		path     string
+		endpoint endpoint
+	}{
+		{"/routes/protocol/R1", ProtoRoutes},
+		{"/routes/protocol/R1/all", ProtoRoutesAll},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", test.path, nil)
+		Endpoint(test.endpoint)(w, r, httprouter.Params{{Key: "protocol", Value: "R1"}})
+
+		if w.Header().Get("Content-Length") != "" {
+			t.Error("Expected the routes to be streamed for", test.path)
+		}
+		if w.Body.Len() <= 1024 || !bytes.Contains(w.Body.Bytes(), []byte(`"routes"`)) {
+			t.Error("Unexpected body for", test.path, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+// Routes are selected if they have all or any of the
+// communities requested with ?community and ?large_community
+const (
+	communityMatchAll = "all"
+	communityMatchAny = "any"
+)
+
+// communityFilter selects routes by their (large) communities
+type communityFilter struct {
+	communities      [][]int64
+	largeCommunities [][]int64
+	matchAll         bool
+}
+
+// Parse a community like "65000:100" with the given
+// number of parts, each at most max.
+func parseCommunity(value string, parts int, max uint64) ([]int64, error) {
+	tokens := strings.Split(value, ":")
+	if len(tokens) != parts {
+		return nil, fmt.Errorf("invalid community: %s", value)
+	}
+	community := make([]int64, 0, parts)
+	for _, token := range tokens {
+		n, err := strconv.ParseUint(token, 10, 64)
+		if err != nil || n > max {
+			return nil, fmt.Errorf("invalid community: %s", value)
+		}
+		community = append(community, int64(n))
+	}
+	return community, nil
+}
+
+// Get the community filter from the query. Nil is
+// returned, if no communities are requested.
+func queryCommunities(r *http.Request) (*communityFilter, error) {
+	filter := &communityFilter{matchAll: true}
+
+	for _, value := range queryList(r, "community") {
+		community, err := parseCommunity(value, 2, 0xffff)
+		if err != nil {
+			return nil, err
+		}
+		filter.communities = append(filter.communities, community)
+	}
+	for _, value := range queryList(r, "large_community") {
+		community, err := parseCommunity(value, 3, 0xffffffff)
+		if err != nil {
+			return nil, err
+		}
+		filter.largeCommunities = append(filter.largeCommunities, community)
+	}
+
+	qs := r.URL.Query()
+	if len(qs["community_match"]) > 1 {
+		return nil, fmt.Errorf("need community_match as single query parameter")
+	}
+	if len(qs["community_match"]) == 1 {
+		switch qs["community_match"][0] {
+		case communityMatchAll:
+		case communityMatchAny:
+			filter.matchAll = false
+		default:
+			return nil, fmt.Errorf("community_match must be either 'all' or 'any'")
+		}
+	}
+
+	if len(filter.communities) == 0 && len(filter.largeCommunities) == 0 {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// Get the communities of a route, which are decoded
+// as lists of numbers from the redis cache.
+func routeCommunities(bgp bird.Parsed, key string) [][]int64 {
+	switch communities := bgp[key].(type) {
+	case [][]int64:
+		return communities
+	case []interface{}:
+		res := make([][]int64, 0, len(communities))
+		for _, c := range communities {
+			values, _ := c.([]interface{})
+			community := make([]int64, 0, len(values))
+			for _, v := range values {
+				n, _ := parsedNumber(v)
+				community = append(community, int64(n))
+			}
+			res = append(res, community)
+		}
+		return res
+	}
+	return nil
+}
+
+func hasCommunity(communities [][]int64, community []int64) bool {
+	for _, c := range communities {
+		if len(c) != len(community) {
+			continue
+		}
+		match := true
+		for i := range c {
+			if c[i] != community[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if the route has all or any of the communities
+func (f *communityFilter) matches(route bird.Parsed) bool {
+	bgp, _ := parsedMap(route["bgp"])
+	communities := routeCommunities(bgp, "communities")
+	largeCommunities := routeCommunities(bgp, "large_communities")
+
+	found := 0
+	for _, c := range f.communities {
+		if hasCommunity(communities, c) {
+			found++
+		}
+	}
+	for _, c := range f.largeCommunities {
+		if hasCommunity(largeCommunities, c) {
+			found++
+		}
+	}
+
+	if f.matchAll {
+		return found == len(f.communities)+len(f.largeCommunities)
+	}
+	return found > 0
+}
+
+// Reduce the routes in the result to the routes with
+// the communities requested.
+func selectRouteCommunities(r *http.Request, res bird.Parsed) {
+	filter, err := queryCommunities(r)
+	if err != nil || filter == nil {
+		return
+	}
+
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return
+	}
+
+	selected := make([]bird.Parsed, 0, len(routes))
+	for _, route := range routes {
+		if filter.matches(route) {
+			selected = append(selected, route)
+		}
+	}
+	res["routes"] = selected
+}
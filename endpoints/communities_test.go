@@ -0,0 +1,77 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestQueryCommunitiesInvalid(t *testing.T) {
+	queries := []string{
+		"?community=65000",
+		"?community=65000:100:1",
+		"?community=65536:100",
+		"?community=foo:bar",
+		"?large_community=65000:100",
+		"?large_community=4294967296:1:1",
+		"?community=65000:100&community_match=most",
+	}
+	for _, query := range queries {
+		r := httptest.NewRequest("GET", "/routes/table/master"+query, nil)
+		if _, err := queryCommunities(r); err == nil {
+			t.Error("Expected an error for", query)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/routes/table/master", nil)
+	if filter, err := queryCommunities(r); err != nil || filter != nil {
+		t.Error("Expected no filter without communities, got:", filter, err)
+	}
+}
+
+func TestSelectRouteCommunities(t *testing.T) {
+	routes := []bird.Parsed{
+		{"network": "10.0.0.0/8", "bgp": bird.Parsed{
+			"communities":       [][]int64{{65000, 100}, {65000, 200}},
+			"large_communities": [][]int64{{65000, 1, 2}},
+		}},
+		{"network": "10.1.0.0/16", "bgp": bird.Parsed{
+			"communities": [][]int64{{65000, 100}},
+		}},
+		{"network": "10.2.0.0/16"},
+		// Decoded from the redis cache
+		{"network": "10.3.0.0/16", "bgp": map[string]interface{}{
+			"communities": []interface{}{[]interface{}{float64(65000), float64(200)}},
+		}},
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"?community=65000:100", []string{"10.0.0.0/8", "10.1.0.0/16"}},
+		{"?community=65000:100,65000:200", []string{"10.0.0.0/8"}},
+		{"?community=65000:100,65000:200&community_match=any",
+			[]string{"10.0.0.0/8", "10.1.0.0/16", "10.3.0.0/16"}},
+		{"?large_community=65000:1:2", []string{"10.0.0.0/8"}},
+		{"?community=65000:300", []string{}},
+	}
+	for _, test := range tests {
+		res := bird.Parsed{"routes": routes}
+		r := httptest.NewRequest("GET", "/routes/table/master"+test.query, nil)
+		selectRouteCommunities(r, res)
+
+		networks := routeNetworks(res)
+		if len(networks) != len(test.expected) {
+			t.Error(test.query, "selected routes:", networks, "expected:", test.expected)
+			continue
+		}
+		for i := range networks {
+			if networks[i] != test.expected[i] {
+				t.Error(test.query, "selected routes:", networks, "expected:", test.expected)
+				break
+			}
+		}
+	}
+}
@@ -5,8 +5,134 @@ type ServerConfig struct {
 	AllowFrom      []string `toml:"allow_from"`
 	ModulesEnabled []string `toml:"modules_enabled"`
 	AllowUncached  bool     `toml:"allow_uncached"`
+	AdminTokens    []string `toml:"admin_tokens"`
+
+	// Tables and protocols which may be queried,
+	// all if not configured
+	AllowTables    []string `toml:"allow_tables"`
+	AllowProtocols []string `toml:"allow_protocols"`
+
+	// Data endpoints are unavailable while this file exists
+	MaintenanceFile string `toml:"maintenance_file"`
+
+	// The enabled modules are loaded from this file
+	// instead, which is reloaded on SIGHUP
+	ModulesFile string `toml:"modules_file"`
+
+	// AllowFrom overrides by module
+	ModulesAllowFrom map[string][]string `toml:"modules_allow_from"`
+
+	// Modules always querying BIRD, bypassing the cache
+	ModulesUncached []string `toml:"modules_uncached"`
+
+	// Logging of requests denied by allow_from or auth
+	LogDenied      string `toml:"log_denied"`
+	LogDeniedLimit int    `toml:"log_denied_limit"`
 
 	EnableTLS bool   `toml:"enable_tls"`
 	Crt       string `toml:"crt"`
 	Key       string `toml:"key"`
+
+	// Accept HTTP/2 on plaintext listeners
+	EnableH2C bool `toml:"enable_h2c"`
+
+	// Redirect requests with a trailing slash or a wrongly
+	// cased or unclean path, both enabled if not configured
+	RedirectTrailingSlash *bool `toml:"redirect_trailing_slash"`
+	RedirectFixedPath     *bool `toml:"redirect_fixed_path"`
+
+	// Status of the responses to requests for the
+	// routes of disabled modules, defaults to 403
+	ModuleDisabledStatus int `toml:"module_disabled_status"`
+
+	// Omit empty fields of routes, unless requested
+	// otherwise with ?omitempty=false
+	OmitEmpty bool `toml:"omit_empty"`
+
+	// Responses up to this size (in bytes) are buffered
+	// to provide Content-Length and ETag
+	ResponseBuffer int `toml:"response_buffer"`
+	// Log clients disconnecting while the response is written
+	LogDisconnects bool `toml:"log_disconnects"`
+
+	// Table used if a request does not specify one
+	DefaultTable string `toml:"default_table"`
+
+	// Concurrent connections per client IP, requests of
+	// further connections are rejected. 0 is unlimited.
+	ConnLimit       int      `toml:"conn_limit"`
+	ConnLimitExempt []string `toml:"conn_limit_exempt"`
+
+	// Socket options of the listeners (Linux only)
+	ListenBacklog int  `toml:"listen_backlog"`
+	ReusePort     bool `toml:"reuse_port"`
+}
+
+// IsModuleEnabled checks if the module is in the list
+// of enabled modules
+func IsModuleEnabled(module string, modulesEnabled []string) bool {
+	for _, enabled := range modulesEnabled {
+		if enabled == module {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Raw endpoint configuration
+type RawConfig struct {
+	Commands       []string `toml:"commands"`
+	Timeout        int      `toml:"timeout"`
+	MaxOutputBytes int64    `toml:"max_output_bytes"`
+	MaxConcurrent  int      `toml:"max_concurrent"`
+}
+
+// Tables used by the net lookups without a table by
+// prefix, e.g. "10.0.0.0/8" = "customers"
+type NetTablesConfig map[string]string
+
+// Human friendly names of next hops and peers,
+// by address or protocol for peers
+type LabelsConfig struct {
+	NextHops map[string]string `toml:"next_hops"`
+	Peers    map[string]string `toml:"peers"`
+}
+
+// Reverse DNS of the next hops, the timeout in ms
+// and the ttl of the cached hostnames in minutes
+type ResolveConfig struct {
+	Timeout    int `toml:"timeout"`
+	CacheTtl   int `toml:"cache_ttl"`
+	MaxLookups int `toml:"max_lookups"`
+}
+
+// Communities marking the RPKI validation state of routes,
+// e.g. "65000:1000:1" as large or "65000:1" as community
+type RpkiConfig struct {
+	Valid    []string `toml:"valid"`
+	Invalid  []string `toml:"invalid"`
+	Unknown  []string `toml:"unknown"`
+	NotFound []string `toml:"not_found"`
+}
+
+// gRPC interface configuration
+type GrpcConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Listen  string `toml:"listen"`
+}
+
+// Collection of the route counts of the BGP protocols,
+// interval in seconds and retention in minutes
+type HistoryConfig struct {
+	Interval  int `toml:"interval"`
+	Retention int `toml:"retention"`
+}
+
+// WebSocket endpoints configuration
+type WebSocketConfig struct {
+	ProtocolsInterval int `toml:"protocols_interval"`
+
+	// Origins of other hosts allowed to connect
+	AllowOrigins []string `toml:"allow_origins"`
 }
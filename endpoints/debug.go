@@ -0,0 +1,62 @@
+package endpoints
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+
+	"github.com/alice-lg/birdwatcher/bird"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	expvar.Publish("birdwatcher", expvar.Func(debugVars))
+}
+
+// Get the internal counters published with expvar
+func debugVars() interface{} {
+	return struct {
+		bird.Stats
+		Goroutines int `json:"goroutines"`
+	}{
+		Stats:      bird.GetStats(),
+		Goroutines: runtime.NumGoroutine(),
+	}
+}
+
+// Pprof serves the runtime profiling data of the net/http/pprof
+// package. The route is only registered when the debug module
+// is enabled and it is subject to the same access control as
+// all other endpoints.
+func Pprof(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if err := CheckAccess(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	switch ps.ByName("profile") {
+	case "/cmdline":
+		pprof.Cmdline(w, r)
+	case "/profile":
+		pprof.Profile(w, r)
+	case "/symbol":
+		pprof.Symbol(w, r)
+	case "/trace":
+		pprof.Trace(w, r)
+	default:
+		pprof.Index(w, r)
+	}
+}
+
+// Expvar serves the variables of the expvar package, including
+// the internal counters as birdwatcher. Like Pprof, the route
+// is only registered when the debug module is enabled.
+func Expvar(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if err := CheckAccess(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	expvar.Handler().ServeHTTP(w, r)
+}
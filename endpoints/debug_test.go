@@ -0,0 +1,24 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExpvar(t *testing.T) {
+	w := httptest.NewRecorder()
+	Expvar(w, httptest.NewRequest("GET", "/debug/vars", nil), nil)
+
+	vars := map[string]json.RawMessage{}
+	if err := json.Unmarshal(w.Body.Bytes(), &vars); err != nil {
+		t.Fatal("Expected the vars as JSON, got:", w.Body.String())
+	}
+	stats := map[string]interface{}{}
+	json.Unmarshal(vars["birdwatcher"], &stats)
+	for _, key := range []string{"cache_size", "worker_pool_size", "workers_busy", "goroutines", "birdc_runs"} {
+		if _, ok := stats[key]; !ok {
+			t.Error("Expected", key, "in the counters, got:", stats)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package endpoints
+
+// Audit log of requests denied by allow_from or auth
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Verbosity of the denied requests log
+const (
+	LogDeniedOff     = "off"
+	LogDeniedOn      = "on"
+	LogDeniedVerbose = "verbose"
+)
+
+// Number of denied requests logged per minute, if
+// not configured. Further denials are only counted.
+const logDeniedDefaultLimit = 60
+
+// deniedLog limits the logged denials to a number per
+// minute, so a flood of requests does not flood the log.
+type deniedLog struct {
+	sync.Mutex
+	window     time.Time
+	count      int
+	suppressed int
+}
+
+var denied = &deniedLog{}
+
+func logDeniedLimit() int {
+	if Conf.LogDeniedLimit > 0 {
+		return Conf.LogDeniedLimit
+	}
+	return logDeniedDefaultLimit
+}
+
+// Check if a denial may be logged in the current window.
+// The number of suppressed denials of the previous
+// window is logged, when a new window starts.
+func (l *deniedLog) allow(now time.Time) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	if now.Sub(l.window) >= time.Minute {
+		if l.suppressed > 0 {
+			log.Println("Denied requests not logged due to the limit:", l.suppressed)
+		}
+		l.window = now
+		l.count = 0
+		l.suppressed = 0
+	}
+
+	if l.count >= logDeniedLimit() {
+		l.suppressed++
+		return false
+	}
+	l.count++
+	return true
+}
+
+// Get the client IP of a request
+func clientAddr(req *http.Request) string {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return ip
+}
+
+// Log a request which was denied with the reason
+func logDenied(req *http.Request, reason error) {
+	if Conf.LogDenied == LogDeniedOff || !denied.allow(time.Now()) {
+		return
+	}
+
+	if Conf.LogDenied == LogDeniedVerbose {
+		module, _ := req.Context().Value(moduleContextKey{}).(string)
+		log.Printf("Denied request from %s: %s %s (module: %q, user agent: %q): %s",
+			clientAddr(req), req.Method, req.URL.Path, module, req.UserAgent(), reason)
+		return
+	}
+	log.Printf("Denied request from %s: %s: %s", clientAddr(req), req.URL.Path, reason)
+}
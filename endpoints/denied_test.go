@@ -0,0 +1,61 @@
+package endpoints
+
+import (
+	"bytes"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogDenied(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log.SetOutput(buf)
+	defer log.SetOutput(os.Stderr)
+
+	Conf = ServerConfig{AllowFrom: []string{"10.0.0.0/8"}}
+	defer func() { Conf = ServerConfig{} }()
+	denied = &deniedLog{}
+
+	r := httptest.NewRequest("GET", "/routes/table/master", nil)
+	r.RemoteAddr = "192.168.1.1:2342"
+	if err := CheckAccess(r); err == nil {
+		t.Fatal("Expected access to be denied")
+	}
+	if !strings.Contains(buf.String(), "192.168.1.1") ||
+		!strings.Contains(buf.String(), "/routes/table/master") {
+		t.Error("Expected client IP and path to be logged, got:", buf.String())
+	}
+
+	buf.Reset()
+	Conf.LogDenied = LogDeniedOff
+	CheckAccess(r)
+	if buf.Len() > 0 {
+		t.Error("Expected no log output, got:", buf.String())
+	}
+}
+
+func TestDeniedLogLimit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log.SetOutput(buf)
+	defer log.SetOutput(os.Stderr)
+
+	Conf = ServerConfig{LogDeniedLimit: 2}
+	defer func() { Conf = ServerConfig{} }()
+
+	l := &deniedLog{}
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		if l.allow(now) != (i < 2) {
+			t.Error("Unexpected limit for denial", i)
+		}
+	}
+	if !l.allow(now.Add(time.Minute)) {
+		t.Error("Expected denials to be logged in the next window")
+	}
+	if !strings.Contains(buf.String(), "limit: 3") {
+		t.Error("Expected suppressed denials to be logged, got:", buf.String())
+	}
+}
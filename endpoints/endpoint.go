@@ -1,10 +1,14 @@
 package endpoints
 
 import (
+	"context"
+	"crypto/subtle"
 	"fmt"
-	"log"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"compress/gzip"
 	"encoding/json"
@@ -15,51 +19,318 @@ import (
 	"github.com/julienschmidt/httprouter"
 )
 
-type endpoint func(*http.Request, httprouter.Params, bool) (bird.Parsed, bool)
+type endpoint func(*http.Request, httprouter.Params, bird.RunOptions) (bird.Parsed, bool)
 
 var Conf ServerConfig
 
+// Error results carry the HTTP status code in-band
+const errorStatusKey = bird.ErrorStatusKey
+
+// ErrorResult creates an endpoint result for a failed
+// request, which is answered with the given HTTP status.
+func ErrorResult(status int, err error) (bird.Parsed, bool) {
+	return bird.Parsed{"error": err.Error(), errorStatusKey: status}, false
+}
+
+// Requests are tagged with the module serving them
+type moduleContextKey struct{}
+
+// WithModule tags the requests handled by a route
+// with the module providing it.
+func WithModule(module string, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		ctx := context.WithValue(r.Context(), moduleContextKey{}, module)
+		handle(w, r.WithContext(ctx), ps)
+	}
+}
+
+// Get the allowed sources for a request. The server-wide
+// list is used, unless it is overridden for the module.
+func allowFrom(req *http.Request) []string {
+	if module, ok := req.Context().Value(moduleContextKey{}).(string); ok {
+		if allowed := Conf.ModulesAllowFrom[module]; len(allowed) > 0 {
+			return allowed
+		}
+	}
+	return Conf.AllowFrom
+}
+
+// CheckAccess checks if the source of the request is
+// allowed. Denied requests are logged.
 func CheckAccess(req *http.Request) error {
-	if len(Conf.AllowFrom) == 0 {
+	err := checkAccess(req)
+	if err != nil {
+		logDenied(req, err)
+	}
+	return err
+}
+
+func checkAccess(req *http.Request) error {
+	allowList := allowFrom(req)
+	if len(allowList) == 0 {
 		return nil // AllowFrom ALL
 	}
 
 	ipStr, _, err := net.SplitHostPort(req.RemoteAddr)
 	if err != nil {
-		log.Println("Error parsing IP address:", err)
 		return fmt.Errorf("error parsing source IP address")
 	}
 	clientIP := net.ParseIP(ipStr)
 	if clientIP == nil {
-		log.Println("Invalid IP address format:", ipStr)
 		return fmt.Errorf("invalid source IP address format")
 	}
-	for _, allowed := range Conf.AllowFrom {
-		if _, allowedNet, err := net.ParseCIDR(allowed); err == nil {
-			if allowedNet.Contains(clientIP) {
-				return nil
+	allowed, _ := ParseNetList(allowList)
+	if NetListContains(allowed, clientIP) {
+		return nil
+	}
+	return fmt.Errorf("%s is not allowed to access this service", ipStr);
+}
+
+// ParseNetList parses a list of addresses and networks in
+// CIDR notation, like the allow_from setting. An address is
+// parsed as a network of a single host. Invalid entries are
+// skipped and reported by the error.
+func ParseNetList(list []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(list))
+	invalid := []string{}
+	for _, entry := range list {
+		cidr := entry
+		if !strings.Contains(cidr, "/") {
+			if strings.Contains(cidr, ":") {
+				cidr += "/128"
+			} else {
+				cidr += "/32"
 			}
-		} else if allowedIP := net.ParseIP(allowed); allowedIP != nil {
-			if allowedIP.Equal(clientIP) {
-				return nil
+		}
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			invalid = append(invalid, entry)
+			continue
+		}
+		nets = append(nets, n)
+	}
+	if len(invalid) > 0 {
+		return nets, fmt.Errorf("invalid IP/CIDR format: %s", strings.Join(invalid, ", "))
+	}
+	return nets, nil
+}
+
+// NetListContains checks if the IP is in one of the networks
+func NetListContains(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAdminAuth checks if the request is authorized with
+// one of the configured admin tokens as bearer token:
+//
+//	Authorization: Bearer <token>
+func CheckAdminAuth(req *http.Request) error {
+	err := checkAdminAuth(req)
+	if err != nil {
+		logDenied(req, err)
+	}
+	return err
+}
+
+func checkAdminAuth(req *http.Request) error {
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return fmt.Errorf("authorization required")
+	}
+	token := []byte(strings.TrimPrefix(auth, "Bearer "))
+
+	for _, admin := range Conf.AdminTokens {
+		if len(admin) > 0 && subtle.ConstantTimeCompare([]byte(admin), token) == 1 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid authorization token")
+}
+
+// The sources exempt from rate limiting, parsed once
+// when the config is loaded
+var rateLimitExempt struct {
+	sync.RWMutex
+	nets []*net.IPNet
+}
+
+// SetRateLimitExemptFrom sets the IPs and CIDRs exempt from
+// rate limiting. Invalid entries are ignored, they are
+// reported when the config is checked at startup.
+func SetRateLimitExemptFrom(exemptFrom []string) {
+	nets, _ := ParseNetList(exemptFrom)
+	rateLimitExempt.Lock()
+	rateLimitExempt.nets = nets
+	rateLimitExempt.Unlock()
+}
+
+// CheckRateLimitExempt checks if the request is exempt from
+// rate limiting, because it is authorized with one of the
+// exempt bearer tokens or comes from an exempt source.
+func CheckRateLimitExempt(req *http.Request) bool {
+	bird.RateLimitConf.RLock()
+	tokens := bird.RateLimitConf.Conf.ExemptTokens
+	bird.RateLimitConf.RUnlock()
+	rateLimitExempt.RLock()
+	exempt := rateLimitExempt.nets
+	rateLimitExempt.RUnlock()
+
+	auth := req.Header.Get("Authorization")
+	if strings.HasPrefix(auth, "Bearer ") {
+		token := []byte(strings.TrimPrefix(auth, "Bearer "))
+		for _, exempt := range tokens {
+			if len(exempt) > 0 && subtle.ConstantTimeCompare([]byte(exempt), token) == 1 {
+				return true
 			}
-		} else {
-			log.Printf("Invalid IP/CIDR format in configuration: %s\n", allowed);
 		}
 	}
-	log.Println("Rejecting access from:", ipStr);
-	return fmt.Errorf("%s is not allowed to access this service", ipStr);
+
+	if len(exempt) == 0 {
+		return false
+	}
+	ipStr, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	clientIP := net.ParseIP(ipStr)
+	if clientIP == nil {
+		return false
+	}
+	return NetListContains(exempt, clientIP)
 }
 
+// CheckUseCache checks if the cache is used for the request.
+// Bypassing the cache with ?uncached=true is allowed for
+// requests authorized with an admin token, and for all
+// clients only if allow_uncached is enabled. The cache is
+// never used for the modules in modules_uncached.
 func CheckUseCache(req *http.Request) bool {
+	if isModuleUncached(req) {
+		return false
+	}
+
 	qs := req.URL.Query()
 
-	if Conf.AllowUncached &&
-		len(qs["uncached"]) == 1 && qs["uncached"][0] == "true" {
+	if len(qs["uncached"]) != 1 || qs["uncached"][0] != "true" {
+		return true
+	}
+	if Conf.AllowUncached {
 		return false
 	}
 
-	return true
+	return checkAdminAuth(req) != nil
+}
+
+// Check if the cache is bypassed for the module
+// of the request by the config.
+func isModuleUncached(req *http.Request) bool {
+	module, ok := req.Context().Value(moduleContextKey{}).(string)
+	if !ok {
+		return false
+	}
+	for _, m := range Conf.ModulesUncached {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
+
+// Raw birdc output is only included in the response
+// if requested and kept by the parser.
+func CheckIncludeRaw(req *http.Request) bool {
+	qs := req.URL.Query()
+
+	return bird.ParserConf.RawOutput &&
+		len(qs["include_raw"]) == 1 && qs["include_raw"][0] == "true"
+}
+
+// Validate the query parameters selecting
+// and ordering the routes in the result.
+func checkRouteQuery(r *http.Request) error {
+	if _, err := queryPaths(r); err != nil {
+		return err
+	}
+	if _, _, err := querySort(r); err != nil {
+		return err
+	}
+	if _, err := queryCommunities(r); err != nil {
+		return err
+	}
+	if _, err := queryOmitEmpty(r); err != nil {
+		return err
+	}
+	if _, err := queryResolve(r); err != nil {
+		return err
+	}
+	if _, err := queryRpki(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get the media types of the Accept header of the request
+// in their order. Media types with q=0 are not acceptable
+// and skipped.
+func acceptedMediaTypes(req *http.Request) []string {
+	mediaTypes := []string{}
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		params := strings.Split(accept, ";")
+		if acceptQuality(params[1:]) == 0 {
+			continue
+		}
+		mediaTypes = append(mediaTypes, strings.TrimSpace(params[0]))
+	}
+	return mediaTypes
+}
+
+// Get the quality of a media type from its params,
+// defaults to 1 if not given or invalid.
+func acceptQuality(params []string) float64 {
+	for _, param := range params {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+		if err != nil {
+			return 1
+		}
+		return q
+	}
+	return 1
+}
+
+// Check if plain text is preferred over JSON
+// in the Accept header of the request.
+func acceptsPlainText(req *http.Request) bool {
+	for _, mediaType := range acceptedMediaTypes(req) {
+		switch mediaType {
+		case "text/plain":
+			return true
+		case "application/json":
+			return false
+		}
+	}
+	return false
+}
+
+// Get the number of routes of a count result. Results
+// from the redis cache are decoded as float.
+func plainTextCount(res bird.Parsed) (int64, bool) {
+	switch count := res["routes"].(type) {
+	case int64:
+		return count, true
+	case float64:
+		return int64(count), true
+	}
+	return 0, false
 }
 
 func Endpoint(wrapped endpoint) httprouter.Handle {
@@ -67,48 +338,166 @@ func Endpoint(wrapped endpoint) httprouter.Handle {
 		r *http.Request,
 		ps httprouter.Params) {
 
+		// The sizes of the response are recorded, e.g. for
+		// the query log, after it is written.
+		var serialized *countingWriter
+		counted := &countingResponse{ResponseWriter: w}
+		defer func(w http.ResponseWriter) {
+			recordResponseSize(w, counted.n, serialized)
+		}(w)
+		w = counted
+
+		w.Header().Set(RequestIDHeader, requestID(r))
+
 		// Access Control
 		if err := CheckAccess(r); err != nil {
 			http.Error(w, err.Error(), http.StatusForbidden)
 			return
 		}
 
+		countRequest(r, ps)
+
+		if ok, message := maintenanceStatus(); ok && !isMaintenanceExempt(r) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			js, _ := json.Marshal(bird.Parsed{"error": message})
+			w.Write(js)
+			return
+		}
+
+		if err := checkAllowedNames(r, ps); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			js, _ := json.Marshal(bird.Parsed{"error": err.Error()})
+			w.Write(js)
+			return
+		}
+
+		if err := checkRouteQuery(r); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			js, _ := json.Marshal(bird.Parsed{"error": err.Error()})
+			w.Write(js)
+			return
+		}
+
 		res := make(map[string]interface{})
 
-		useCache := CheckUseCache(r)
-		ret, from_cache := wrapped(r, ps, useCache)
+		module, _ := r.Context().Value(moduleContextKey{}).(string)
+		opts := bird.RunOptions{
+			UseCache: CheckUseCache(r),
+			Exempt:   CheckRateLimitExempt(r),
+			Module:   module,
+			Raw:      CheckIncludeRaw(r),
+		}
+		ret, from_cache := wrapped(r, ps, opts)
 
 		if reflect.DeepEqual(ret, bird.NilParse) {
 			w.WriteHeader(http.StatusTooManyRequests)
 			return
 		}
-		if reflect.DeepEqual(ret, bird.BirdError) {
-			w.WriteHeader(http.StatusInternalServerError)
+		if reflect.DeepEqual(ret, bird.BirdNotReady) {
 			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", "5")
+			w.WriteHeader(http.StatusServiceUnavailable)
 			js, _ := json.Marshal(ret)
 			w.Write(js)
 			return
 		}
+		if reflect.DeepEqual(ret, bird.BirdError) ||
+			reflect.DeepEqual(ret, bird.BirdOutputTooLarge) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			js, _ := json.Marshal(ret)
+			w.Write(js)
+			return
+		}
+		if status, ok := ret[errorStatusKey].(int); ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			js, _ := json.Marshal(bird.Parsed{"error": ret["error"]})
+			w.Write(js)
+			return
+		}
 		res["api"] = GetApiInfo(&ret, from_cache)
 
 		for k, v := range ret {
 			res[k] = v
 		}
+		if !CheckIncludeRaw(r) {
+			delete(res, "_raw")
+		}
+		if stale, _ := res[bird.StaleKey].(bool); stale {
+			w.Header().Set("X-Birdwatcher-Stale", "true")
+		}
+		delete(res, bird.StaleKey)
+		if timing, ok := res[bird.TimingKey].(bird.Parsed); ok {
+			w.Header().Set("X-Birdwatcher-Exec-Ms", fmt.Sprintf("%.3f", timing["exec_ms"]))
+			w.Header().Set("X-Birdwatcher-Parse-Ms", fmt.Sprintf("%.3f", timing["parse_ms"]))
+		}
+		delete(res, bird.TimingKey)
+
+		// The client is gone, e.g. while waiting for birdc
+		if r.Context().Err() != nil {
+			return
+		}
+
+		setProtocolsUptime(res, time.Now())
+		selectRoutePaths(r, res)
+		selectRouteCommunities(r, res)
+		selectRouteRpki(r, res)
+		sortRoutes(r, res)
+		labelRoutes(res)
+		resolveRoutes(r, res)
+		selectRouteFields(r, res)
+		omitEmptyRouteFields(r, res)
+
+		// The format of the response depends on the Accept header
+		w.Header().Set("Vary", "Accept")
+
+		// Counts are available as plain text for scripts
+		if count, ok := plainTextCount(res); ok && acceptsPlainText(r) {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprintln(w, count)
+			return
+		}
+
+		// Routes are available as MRT dump for BGP tools
+		if routes, ok := parsedList(res["routes"]); ok && acceptsMrt(r) {
+			w.Header().Set("Content-Type", "application/mrt")
+			out := newResponseBuffer(w, responseBufferSize())
+			serialized = &countingWriter{w: out}
+			if err := writeMrt(serialized, routes, time.Now()); err != nil {
+				// Nothing is written anymore after a failed write
+				logWriteError(r, err)
+				return
+			}
+			out.finish(r)
+			return
+		}
 
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		// Small responses are buffered, large ones streamed
+		out := newResponseBuffer(w, responseBufferSize())
 
 		// Check if compression is supported
 		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
 			// Compress response
 			w.Header().Set("Content-Encoding", "gzip")
-			gz := gzip.NewWriter(w)
-			defer gz.Close()
-			json := json.NewEncoder(gz)
+			gz := gzip.NewWriter(out)
+			serialized = &countingWriter{w: gz}
+			json := json.NewEncoder(serialized)
 			json.Encode(res)
+			gz.Close()
 		} else {
-			json := json.NewEncoder(w)
+			serialized = &countingWriter{w: out}
+			json := json.NewEncoder(serialized)
 			json.Encode(res) // Fall back to uncompressed response
 		}
+		out.finish(r)
 	}
 }
 
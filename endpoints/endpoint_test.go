@@ -0,0 +1,339 @@
+package endpoints
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestCheckRateLimitExempt(t *testing.T) {
+	bird.RateLimitConf.Lock()
+	bird.RateLimitConf.Conf.ExemptTokens = []string{"secret"}
+	bird.RateLimitConf.Unlock()
+	SetRateLimitExemptFrom([]string{"10.23.0.0/16", "2001:db8::1", "foo"})
+	defer func() {
+		bird.RateLimitConf.Lock()
+		bird.RateLimitConf.Conf.ExemptTokens = nil
+		bird.RateLimitConf.Unlock()
+		SetRateLimitExemptFrom(nil)
+	}()
+
+	tests := []struct {
+		remoteAddr string
+		auth       string
+		exempt     bool
+	}{
+		{"192.0.2.1:4242", "", false},
+		{"192.0.2.1:4242", "Bearer secret", true},
+		{"192.0.2.1:4242", "Bearer wrong", false},
+		{"10.23.42.1:4242", "", true},
+		{"[2001:db8::1]:4242", "", true},
+		{"[2001:db8::2]:4242", "", false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/status", nil)
+		r.RemoteAddr = test.remoteAddr
+		if test.auth != "" {
+			r.Header.Set("Authorization", test.auth)
+		}
+		if exempt := CheckRateLimitExempt(r); exempt != test.exempt {
+			t.Error("Expected exempt to be", test.exempt, "for", test.remoteAddr, test.auth)
+		}
+	}
+}
+
+func TestParseNetList(t *testing.T) {
+	nets, err := ParseNetList([]string{"10.23.0.0/16", "192.0.2.1", "2001:db8::1", "invalid", "10.0.0.0/33"})
+	if err == nil || err.Error() != "invalid IP/CIDR format: invalid, 10.0.0.0/33" {
+		t.Error("Expected the invalid entries to be reported, got:", err)
+	}
+	if len(nets) != 3 {
+		t.Fatal("Expected 3 networks, got:", nets)
+	}
+
+	tests := []struct {
+		ip       string
+		contains bool
+	}{
+		{"10.23.42.1", true},
+		{"192.0.2.1", true},
+		{"192.0.2.2", false},
+		{"2001:db8::1", true},
+		{"2001:db8::2", false},
+	}
+	for _, test := range tests {
+		if contains := NetListContains(nets, net.ParseIP(test.ip)); contains != test.contains {
+			t.Error("Expected", test.ip, "contained:", test.contains, "got:", contains)
+		}
+	}
+
+	if _, err := ParseNetList(nil); err != nil {
+		t.Error("Expected empty list to be valid, got:", err)
+	}
+}
+
+func TestCheckUseCache(t *testing.T) {
+	Conf.AdminTokens = []string{"admin"}
+	defer func() { Conf = ServerConfig{} }()
+
+	tests := []struct {
+		query         string
+		auth          string
+		allowUncached bool
+		useCache      bool
+	}{
+		{"", "", false, true},
+		{"?uncached=true", "", false, true},
+		{"?uncached=true", "Bearer wrong", false, true},
+		{"?uncached=true", "Bearer admin", false, false},
+		{"?uncached=true", "", true, false},
+		{"", "Bearer admin", false, true},
+	}
+	for _, test := range tests {
+		Conf.AllowUncached = test.allowUncached
+		r := httptest.NewRequest("GET", "/status"+test.query, nil)
+		if test.auth != "" {
+			r.Header.Set("Authorization", test.auth)
+		}
+		if useCache := CheckUseCache(r); useCache != test.useCache {
+			t.Error("Expected useCache to be", test.useCache, "for", test.query, test.auth, test.allowUncached)
+		}
+	}
+}
+
+func TestCheckAccessModuleOverride(t *testing.T) {
+	Conf.AllowFrom = []string{"192.0.2.0/24"}
+	Conf.ModulesAllowFrom = map[string][]string{
+		"routes_export": {"10.0.0.1"},
+	}
+	defer func() {
+		Conf.AllowFrom = nil
+		Conf.ModulesAllowFrom = nil
+	}()
+
+	tests := []struct {
+		module     string
+		remoteAddr string
+		allowed    bool
+	}{
+		{"status", "192.0.2.1:4242", true},
+		{"status", "10.0.0.1:4242", false},
+		{"routes_export", "192.0.2.1:4242", false},
+		{"routes_export", "10.0.0.1:4242", true},
+	}
+	for _, test := range tests {
+		var err error
+		handle := WithModule(test.module, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			err = CheckAccess(r)
+		})
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = test.remoteAddr
+		handle(httptest.NewRecorder(), r, nil)
+
+		if (err == nil) != test.allowed {
+			t.Error("Expected access to", test.module, "from", test.remoteAddr, "to be", test.allowed)
+		}
+	}
+}
+
+func TestAcceptsPlainText(t *testing.T) {
+	tests := []struct {
+		accept string
+		plain  bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"text/plain", true},
+		{"text/plain; charset=utf-8", true},
+		{"application/json, text/plain", false},
+		{"text/plain;q=0.9, application/json", true},
+		{"text/plain;q=0, application/json", false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/routes/count/table/master", nil)
+		r.Header.Set("Accept", test.accept)
+		if acceptsPlainText(r) != test.plain {
+			t.Error("Expected plain text to be", test.plain, "for Accept:", test.accept)
+		}
+	}
+}
+
+func TestPlainTextCount(t *testing.T) {
+	if count, ok := plainTextCount(bird.Parsed{"routes": int64(42)}); !ok || count != 42 {
+		t.Error("Expected count 42, got:", count)
+	}
+	if count, ok := plainTextCount(bird.Parsed{"routes": float64(42)}); !ok || count != 42 {
+		t.Error("Expected count 42 from redis, got:", count)
+	}
+	if _, ok := plainTextCount(bird.Parsed{"routes": []bird.Parsed{}}); ok {
+		t.Error("Expected routes not to be a count")
+	}
+}
+
+func TestEndpointNotFound(t *testing.T) {
+	handle := Endpoint(func(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+		return bird.NotFound("No such protocol R192_999"), false
+	})
+
+	w := httptest.NewRecorder()
+	handle(w, httptest.NewRequest("GET", "/protocols/R192_999", nil), nil)
+
+	if w.Code != http.StatusNotFound {
+		t.Error("Expected status 404, got:", w.Code)
+	}
+	expected := `{"error":"No such protocol R192_999"}`
+	if w.Body.String() != expected {
+		t.Error("Expected", expected, "got:", w.Body.String())
+	}
+}
+
+func TestEndpointBirdError(t *testing.T) {
+	for _, res := range []bird.Parsed{bird.BirdError, bird.BirdOutputTooLarge} {
+		handle := Endpoint(func(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+			return res, false
+		})
+
+		w := httptest.NewRecorder()
+		handle(w, httptest.NewRequest("GET", "/status", nil), nil)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Error("Expected status 500, got:", w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Error("Expected a JSON error, got:", ct)
+		}
+	}
+}
+
+func TestEndpointTimingHeaders(t *testing.T) {
+	handle := Endpoint(func(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+		return bird.Parsed{
+			"routes":       []bird.Parsed{},
+			bird.TimingKey: bird.Parsed{"exec_ms": 12.5, "parse_ms": 0.25},
+		}, false
+	})
+
+	w := httptest.NewRecorder()
+	handle(w, httptest.NewRequest("GET", "/routes/table/master", nil), nil)
+
+	if h := w.Header().Get("X-Birdwatcher-Exec-Ms"); h != "12.500" {
+		t.Error("Unexpected exec time header:", h)
+	}
+	if h := w.Header().Get("X-Birdwatcher-Parse-Ms"); h != "0.250" {
+		t.Error("Unexpected parse time header:", h)
+	}
+	if strings.Contains(w.Body.String(), bird.TimingKey) {
+		t.Error("Expected timing to be removed from the body:", w.Body.String())
+	}
+}
+
+func TestEndpointRequestID(t *testing.T) {
+	handle := Endpoint(func(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+		return bird.Parsed{}, false
+	})
+
+	tests := []struct {
+		id        string
+		preserved bool
+	}{
+		{"", false},
+		{"abc-123", true},
+		{"with space", false},
+		{strings.Repeat("a", 200), false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/status", nil)
+		if test.id != "" {
+			r.Header.Set(RequestIDHeader, test.id)
+		}
+		w := httptest.NewRecorder()
+		handle(w, r, nil)
+
+		id := w.Header().Get(RequestIDHeader)
+		if test.preserved && id != test.id {
+			t.Error("Expected request ID", test.id, "got:", id)
+		}
+		if !test.preserved && (id == test.id || len(id) != 36) {
+			t.Error("Expected a generated request ID for", test.id, "got:", id)
+		}
+	}
+}
+
+func TestCheckUseCacheModuleUncached(t *testing.T) {
+	Conf.ModulesUncached = []string{"status"}
+	defer func() { Conf = ServerConfig{} }()
+
+	tests := []struct {
+		module   string
+		useCache bool
+	}{
+		{"status", false},
+		{"routes_table", true},
+		{"", true},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/status", nil)
+		if test.module != "" {
+			r = r.WithContext(context.WithValue(r.Context(), moduleContextKey{}, test.module))
+		}
+		if useCache := CheckUseCache(r); useCache != test.useCache {
+			t.Error("Expected useCache to be", test.useCache, "for module", test.module)
+		}
+	}
+}
+
+func TestEndpointVary(t *testing.T) {
+	handle := Endpoint(func(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+		return bird.Parsed{"routes": []bird.Parsed{{"network": "10.0.0.0/8"}}}, false
+	})
+
+	for _, accept := range []string{"application/json", "application/mrt"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/routes/table/master", nil)
+		r.Header.Set("Accept", accept)
+		handle(w, r, nil)
+
+		vary := w.Header()["Vary"]
+		if len(vary) == 0 || vary[0] != "Accept" {
+			t.Error("Expected Vary: Accept for", accept, "got:", vary)
+		}
+	}
+}
+
+// Fake birdc run by the tests as the test binary, see
+// helperBirdc. It prints the sample in the environment.
+func TestHelperBirdc(t *testing.T) {
+	sample := os.Getenv("BIRDWATCHER_TEST_SAMPLE")
+	if sample == "" {
+		return
+	}
+	f, err := os.Open("../test/" + sample)
+	if err != nil {
+		os.Exit(1)
+	}
+	io.Copy(os.Stdout, f)
+	os.Exit(0)
+}
+
+// Use the fake birdc printing the sample with an empty
+// cache. The returned function restores the config.
+func helperBirdc(sample string) func() {
+	os.Setenv("BIRDWATCHER_TEST_SAMPLE", sample)
+	bird.ClientConf.BirdCmd = os.Args[0] + " -test.run=^TestHelperBirdc$ --"
+	bird.BirdVersion = 1
+	bird.InitializeCache()
+	return func() {
+		os.Unsetenv("BIRDWATCHER_TEST_SAMPLE")
+		bird.ClientConf = bird.BirdConfig{}
+		bird.BirdVersion = 0
+	}
+}
@@ -0,0 +1,71 @@
+package endpoints
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+// Alternative names for route fields
+var routeFieldAliases = map[string]string{
+	"prefix":   "network",
+	"next_hop": "gateway",
+	"protocol": "from_protocol",
+}
+
+// Copy the requested fields of a route. Fields of
+// nested objects are selected like "bgp.as_path".
+func projectRouteFields(route bird.Parsed, fields []string) bird.Parsed {
+	res := bird.Parsed{}
+	for _, field := range fields {
+		if alias, ok := routeFieldAliases[field]; ok {
+			field = alias
+		}
+
+		path := strings.SplitN(field, ".", 2)
+		value, ok := route[path[0]]
+		if !ok {
+			continue
+		}
+		if len(path) == 1 {
+			res[field] = value
+			continue
+		}
+
+		nested, ok := parsedMap(value)
+		if !ok {
+			continue
+		}
+		if value, ok = nested[path[1]]; !ok {
+			continue
+		}
+		selected, ok := res[path[0]].(bird.Parsed)
+		if !ok {
+			selected = bird.Parsed{}
+			res[path[0]] = selected
+		}
+		selected[path[1]] = value
+	}
+	return res
+}
+
+// Reduce the routes in the result to the fields
+// requested with the fields query parameter.
+func selectRouteFields(r *http.Request, res bird.Parsed) {
+	fields := queryList(r, "fields")
+	if len(fields) == 0 {
+		return
+	}
+
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return
+	}
+
+	selected := make([]bird.Parsed, 0, len(routes))
+	for _, route := range routes {
+		selected = append(selected, projectRouteFields(route, fields))
+	}
+	res["routes"] = selected
+}
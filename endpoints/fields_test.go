@@ -0,0 +1,42 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestSelectRouteFields(t *testing.T) {
+	route := bird.Parsed{
+		"network":       "10.0.0.0/8",
+		"gateway":       "192.0.2.1",
+		"from_protocol": "R194_42",
+		"interface":     "eth0",
+		"bgp": bird.Parsed{
+			"as_path":    []string{"1764"},
+			"local_pref": "100",
+		},
+	}
+	res := bird.Parsed{"routes": []bird.Parsed{route}}
+
+	r := httptest.NewRequest("GET", "/routes/table/master?fields=prefix,next_hop,bgp.as_path,missing", nil)
+	selectRouteFields(r, res)
+
+	expected := []bird.Parsed{{
+		"network": "10.0.0.0/8",
+		"gateway": "192.0.2.1",
+		"bgp": bird.Parsed{
+			"as_path": []string{"1764"},
+		},
+	}}
+	if !reflect.DeepEqual(res["routes"], expected) {
+		t.Error("Selected fields:", res["routes"], "expected:", expected)
+	}
+
+	// The original route must not be modified
+	if _, ok := route["interface"]; !ok {
+		t.Error("Route was modified by field selection")
+	}
+}
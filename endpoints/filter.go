@@ -57,3 +57,7 @@ func ValidatePrefixParam(value string) (string, error) {
 func ValidateNetMaskParam(value string) (string, error) {
 	return ValidateLengthAndCharset(value, 3, "1234567890")
 }
+
+func ValidateNumberParam(value string) (string, error) {
+	return ValidateLengthAndCharset(value, 10, "1234567890")
+}
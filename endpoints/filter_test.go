@@ -1,7 +1,11 @@
 package endpoints
 
 import (
+	"net/http"
 	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
 )
 
 func TestValidateProtocol(t *testing.T) {
@@ -36,3 +40,98 @@ func TestValidateProtocol(t *testing.T) {
 	}
 
 }
+
+func TestValidateAddr(t *testing.T) {
+	validAddrs := []string{
+		"10.23.42.1",
+		"2001:db8::1",
+		"::ffff:10.0.0.1",
+	}
+	invalidAddrs := []string{
+		"10.23.42.0/24",
+		"2001:db8::/32",
+		"10.23.42",
+		"example.com",
+	}
+
+	for _, param := range validAddrs {
+		if _, err := validateAddrParam(param); err != nil {
+			t.Error(param, "should be a valid address param:", err)
+		}
+	}
+	for _, param := range invalidAddrs {
+		if _, err := validateAddrParam(param); err == nil {
+			t.Error(param, "should be an invalid address param")
+		}
+	}
+}
+
+func TestValidateAS(t *testing.T) {
+	for _, param := range []string{"0", "65000", "4294967295"} {
+		if _, err := validateASParam(param); err != nil {
+			t.Error(param, "should be a valid AS param")
+		}
+	}
+	for _, param := range []string{"", "4294967296", "-1", "AS65000", "65000 or 1"} {
+		if _, err := validateASParam(param); err == nil {
+			t.Error(param, "should be an invalid AS param")
+		}
+	}
+}
+
+func TestRouteNetPeerValidation(t *testing.T) {
+	tests := []struct {
+		net  string
+		peer string
+	}{
+		{"10.0.0.0/8", "10.0.0.0/8"},
+		{"10.0.0.0/8", "peer1'"},
+		{"10.0.0.0/8", ""},
+		{"not a net", "192.0.2.1"},
+	}
+	for _, test := range tests {
+		ps := httprouter.Params{{Key: "net", Value: test.net}, {Key: "peer", Value: test.peer}}
+		res, _ := RouteNetPeer(nil, ps, bird.RunOptions{})
+		if res[bird.ErrorStatusKey] != http.StatusBadRequest {
+			t.Error("Expected bad request for", test.net, test.peer, "got:", res)
+		}
+	}
+}
+
+func TestRouteNetMaskValidation(t *testing.T) {
+	tests := []struct {
+		net  string
+		mask string
+	}{
+		{"10.0.0.0", "33"},
+		{"10.0.0.0", "mask"},
+		{"not a net", "8"},
+	}
+	for _, test := range tests {
+		ps := httprouter.Params{{Key: "net", Value: test.net}, {Key: "mask", Value: test.mask}}
+		res, _ := RouteNetMask(nil, ps, bird.RunOptions{})
+		if res[bird.ErrorStatusKey] != http.StatusBadRequest {
+			t.Error("Expected bad request for", test.net, test.mask, "got:", res)
+		}
+	}
+}
+
+func TestRouteNetPeer(t *testing.T) {
+	defer helperBirdc("routes_bird1_ipv4.sample")()
+
+	for _, peer := range []string{"192.0.2.1", "R1", "R_192_0_2_1"} {
+		ps := httprouter.Params{{Key: "net", Value: "10.0.0.0/8"}, {Key: "peer", Value: peer}}
+		res, _ := RouteNetPeer(nil, ps, bird.RunOptions{})
+		if routes, ok := res["routes"].([]bird.Parsed); !ok || len(routes) == 0 {
+			t.Error("Expected routes for peer", peer, "got:", res)
+		}
+	}
+
+	Conf.AllowProtocols = []string{"R1"}
+	defer func() { Conf.AllowProtocols = nil }()
+	ps := httprouter.Params{{Key: "net", Value: "10.0.0.0/8"}, {Key: "peer", Value: "R2"}}
+	res, _ := RouteNetPeer(nil, ps, bird.RunOptions{})
+	if res[bird.ErrorStatusKey] != http.StatusForbidden {
+		t.Error("Expected protocol not to be allowed, got:", res)
+	}
+}
@@ -0,0 +1,207 @@
+package endpoints
+
+// gRPC interface mirroring the core HTTP endpoints. The
+// service is defined in pb/birdwatcher.proto.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/alice-lg/birdwatcher/pb"
+	"github.com/julienschmidt/httprouter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var GrpcConf GrpcConfig
+
+// A method of the gRPC service is served by the endpoint
+// of a module. The param of the request is passed to the
+// endpoint as route param.
+type grpcMethod struct {
+	name     string
+	module   string
+	param    string
+	endpoint endpoint
+}
+
+// Map the HTTP status of an error result to a gRPC code
+var grpcErrorCodes = map[int]codes.Code{
+	http.StatusBadRequest:      codes.InvalidArgument,
+	http.StatusForbidden:       codes.PermissionDenied,
+	http.StatusNotFound:        codes.NotFound,
+	http.StatusTooManyRequests: codes.ResourceExhausted,
+}
+
+// Convert the result of an endpoint for a gRPC response.
+// The result is decoded from JSON like by clients of the
+// HTTP endpoints, so it does not depend on the cache.
+func grpcResult(ret bird.Parsed, fromCache bool) (*pb.Api, map[string]interface{}, error) {
+	switch {
+	case reflect.DeepEqual(ret, bird.NilParse):
+		return nil, nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	case reflect.DeepEqual(ret, bird.BirdNotReady):
+		return nil, nil, status.Error(codes.Unavailable, bird.BirdNotReady["error"].(string))
+	case reflect.DeepEqual(ret, bird.BirdError),
+		reflect.DeepEqual(ret, bird.BirdOutputTooLarge):
+		return nil, nil, status.Error(codes.Internal, ret["error"].(string))
+	}
+	if code, ok := ret[errorStatusKey].(int); ok {
+		message, _ := ret["error"].(string)
+		if c, ok := grpcErrorCodes[code]; ok {
+			return nil, nil, status.Error(c, message)
+		}
+		return nil, nil, status.Error(codes.Internal, message)
+	}
+	if message, ok := ret["error"].(string); ok && len(ret) == 1 {
+		return nil, nil, status.Error(codes.InvalidArgument, message)
+	}
+
+	// The uptime is set like for HTTP responses
+	copied := bird.Parsed{}
+	for key, value := range ret {
+		copied[key] = value
+	}
+	setProtocolsUptime(copied, time.Now())
+
+	js, err := json.Marshal(copied)
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+	res := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	if err := dec.Decode(&res); err != nil {
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+
+	api := &pb.Api{
+		Version:         VERSION,
+		ResultFromCache: fromCache,
+		CachedAt:        jsonString(res["cached_at"]),
+		Ttl:             jsonString(res["ttl"]),
+	}
+	return api, res, nil
+}
+
+// The gRPC service, providing the methods of
+// the enabled modules
+type grpcServer struct {
+	modules []string
+}
+
+// Serve a call with the endpoint of the method. Access is
+// checked like for HTTP requests to the module.
+func (s *grpcServer) call(ctx context.Context, m grpcMethod, value string) (*pb.Api, map[string]interface{}, error) {
+	if !IsModuleEnabled(m.module, s.modules) {
+		return nil, nil, status.Errorf(codes.Unimplemented, "method %s not implemented", m.name)
+	}
+
+	addr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	r := &http.Request{
+		Method:     "GET",
+		URL:        &url.URL{Path: "/" + m.name},
+		Header:     http.Header{},
+		RemoteAddr: addr,
+	}
+	r = r.WithContext(context.WithValue(ctx, moduleContextKey{}, m.module))
+
+	if err := CheckAccess(r); err != nil {
+		return nil, nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if ok, message := maintenanceStatus(); ok && !isMaintenanceExempt(r) {
+		return nil, nil, status.Error(codes.Unavailable, message)
+	}
+
+	ps := httprouter.Params{}
+	if m.param != "" {
+		ps = append(ps, httprouter.Param{Key: m.param, Value: value})
+		if err := checkAllowedNames(r, ps); err != nil {
+			return nil, nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+
+	opts := bird.RunOptions{
+		UseCache: true,
+		Exempt:   CheckRateLimitExempt(r),
+		Module:   m.module,
+	}
+	return grpcResult(m.endpoint(r, ps, opts))
+}
+
+func (s *grpcServer) Status(ctx context.Context, req *pb.StatusRequest) (*pb.StatusResponse, error) {
+	api, res, err := s.call(ctx, grpcMethod{"Status", "status", "", Status}, "")
+	if err != nil {
+		return nil, err
+	}
+	return &pb.StatusResponse{Api: api, Status: statusMessage(res["status"])}, nil
+}
+
+func (s *grpcServer) protocols(ctx context.Context, m grpcMethod) (*pb.ProtocolsResponse, error) {
+	api, res, err := s.call(ctx, m, "")
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProtocolsResponse{Api: api, Protocols: protocolMessages(res["protocols"])}, nil
+}
+
+func (s *grpcServer) Protocols(ctx context.Context, req *pb.ProtocolsRequest) (*pb.ProtocolsResponse, error) {
+	return s.protocols(ctx, grpcMethod{"Protocols", "protocols", "", Protocols})
+}
+
+func (s *grpcServer) ProtocolsBgp(ctx context.Context, req *pb.ProtocolsRequest) (*pb.ProtocolsResponse, error) {
+	return s.protocols(ctx, grpcMethod{"ProtocolsBgp", "protocols_bgp", "", Bgp})
+}
+
+func (s *grpcServer) routes(ctx context.Context, m grpcMethod, value string) (*pb.RoutesResponse, error) {
+	api, res, err := s.call(ctx, m, value)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RoutesResponse{Api: api, Routes: routeMessages(res["routes"])}, nil
+}
+
+func (s *grpcServer) RoutesTable(ctx context.Context, req *pb.RoutesTableRequest) (*pb.RoutesResponse, error) {
+	return s.routes(ctx, grpcMethod{"RoutesTable", "routes_table", "table", TableRoutes}, req.GetTable())
+}
+
+func (s *grpcServer) RoutesProtocol(ctx context.Context, req *pb.RoutesProtocolRequest) (*pb.RoutesResponse, error) {
+	return s.routes(ctx, grpcMethod{"RoutesProtocol", "routes_protocol", "protocol", ProtoRoutes}, req.GetProtocol())
+}
+
+func (s *grpcServer) RouteNet(ctx context.Context, req *pb.RouteNetRequest) (*pb.RoutesResponse, error) {
+	return s.routes(ctx, grpcMethod{"RouteNet", "route_net", "net", RouteNet}, req.GetNet())
+}
+
+// NewGrpcServer creates a gRPC server providing the
+// methods of the enabled modules.
+func NewGrpcServer(modules []string) *grpc.Server {
+	srv := grpc.NewServer()
+	pb.RegisterBirdwatcherServer(srv, &grpcServer{modules: modules})
+	return srv
+}
+
+// ServeGrpc serves the gRPC interface on the configured
+// listen address. It blocks until the server is stopped.
+func ServeGrpc(srv *grpc.Server) error {
+	ln, err := net.Listen("tcp", GrpcConf.Listen)
+	if err != nil {
+		return err
+	}
+	if err := srv.Serve(ln); err != grpc.ErrServerStopped {
+		return err
+	}
+	return nil
+}
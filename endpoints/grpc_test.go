@@ -0,0 +1,135 @@
+package endpoints
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/alice-lg/birdwatcher/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGrpcResult(t *testing.T) {
+	cachedAt := time.Date(2020, 11, 24, 10, 0, 0, 0, time.UTC)
+	api, res, err := grpcResult(bird.Parsed{
+		"routes": []bird.Parsed{{
+			"network": "10.0.0.0/8",
+			"metric":  int64(100),
+			"bgp": bird.Parsed{
+				"as_path":     []string{"65000", "65001"},
+				"communities": [][]int64{{65000, 1}},
+			},
+		}},
+		"cached_at": cachedAt,
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !api.ResultFromCache || api.CachedAt != "2020-11-24T10:00:00Z" {
+		t.Error("Unexpected api info:", api)
+	}
+
+	routes := routeMessages(res["routes"])
+	if len(routes) != 1 {
+		t.Fatal("Expected 1 route, got:", routes)
+	}
+	route := routes[0]
+	if route.Network != "10.0.0.0/8" || route.Metric != 100 {
+		t.Error("Unexpected route:", route)
+	}
+	if len(route.Bgp.AsPath) != 2 || route.Bgp.Communities[0].Values[1] != 1 {
+		t.Error("Unexpected BGP attributes:", route.Bgp)
+	}
+
+	tests := []struct {
+		res  bird.Parsed
+		code codes.Code
+	}{
+		{bird.NilParse, codes.ResourceExhausted},
+		{bird.BirdNotReady, codes.Unavailable},
+		{bird.BirdError, codes.Internal},
+		{bird.NotFound("No such table foo"), codes.NotFound},
+		{bird.Parsed{"error": "Invalid character in param value"}, codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		_, _, err := grpcResult(test.res, false)
+		if status.Code(err) != test.code {
+			t.Error("Expected", test.code, "for", test.res, "got:", err)
+		}
+	}
+}
+
+func TestProtocolMessages(t *testing.T) {
+	_, res, err := grpcResult(bird.Parsed{
+		"protocols": bird.Parsed{
+			"R1": bird.Parsed{
+				"protocol":    "R1",
+				"neighbor_as": int64(65000),
+				"hold_time":   nil,
+				"keepalive":   int64(60),
+				"routes":      bird.Parsed{"imported": int64(12)},
+				"route_changes": bird.Parsed{
+					"import_updates": bird.Parsed{"received": int64(3)},
+				},
+			},
+		},
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	protocol := protocolMessages(res["protocols"])["R1"]
+	if protocol.NeighborAs != 65000 || protocol.Routes.Imported != 12 {
+		t.Error("Unexpected protocol:", protocol)
+	}
+	if protocol.HoldTime != nil || protocol.Keepalive.GetValue() != 60 {
+		t.Error("Unexpected timers:", protocol.HoldTime, protocol.Keepalive)
+	}
+	updates := protocol.RouteChanges.ImportUpdates
+	if updates.Received.GetValue() != 3 || updates.Rejected != nil {
+		t.Error("Unexpected route changes:", updates)
+	}
+}
+
+func TestGrpcServer(t *testing.T) {
+	ln := bufconn.Listen(1 << 20)
+	srv := NewGrpcServer([]string{"routes_table"})
+	go srv.Serve(ln)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return ln.Dial()
+		}),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewBirdwatcherClient(conn)
+	ctx := context.Background()
+
+	// Params are validated like for HTTP requests
+	_, err = client.RoutesTable(ctx, &pb.RoutesTableRequest{Table: "master'"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Error("Expected invalid argument, got:", err)
+	}
+
+	// Methods of disabled modules are not available
+	_, err = client.Status(ctx, &pb.StatusRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Error("Expected unimplemented, got:", err)
+	}
+
+	Conf.AllowTables = []string{"tenant1"}
+	defer func() { Conf.AllowTables = nil }()
+	_, err = client.RoutesTable(ctx, &pb.RoutesTableRequest{Table: "master"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Error("Expected permission denied, got:", err)
+	}
+}
@@ -0,0 +1,175 @@
+package endpoints
+
+// Time series of the route counts of the BGP protocols
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+var HistoryConf HistoryConfig
+
+// Get the interval of the samples from the config
+func historyInterval() time.Duration {
+	if HistoryConf.Interval > 0 {
+		return time.Duration(HistoryConf.Interval) * time.Second
+	}
+	return time.Minute
+}
+
+// Get the number of samples kept per protocol
+// to cover the retention, one day by default.
+func historySize() int {
+	retention := 24 * time.Hour
+	if HistoryConf.Retention > 0 {
+		retention = time.Duration(HistoryConf.Retention) * time.Minute
+	}
+	size := int(retention / historyInterval())
+	if size < 1 {
+		return 1
+	}
+	return size
+}
+
+// A sample of the route counts of a protocol
+type historySample struct {
+	time   time.Time
+	routes bird.Parsed
+}
+
+// historyRing keeps the latest samples, the oldest
+// sample is overwritten when the ring is full.
+type historyRing struct {
+	samples []historySample
+	next    int
+	full    bool
+}
+
+func newHistoryRing(size int) *historyRing {
+	return &historyRing{samples: make([]historySample, size)}
+}
+
+func (r *historyRing) add(sample historySample) {
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Get the samples, oldest first
+func (r *historyRing) list() []historySample {
+	if !r.full {
+		return append([]historySample{}, r.samples[:r.next]...)
+	}
+	return append(
+		append([]historySample{}, r.samples[r.next:]...),
+		r.samples[:r.next]...)
+}
+
+// prefixHistory stores the samples of all BGP protocols
+type prefixHistory struct {
+	sync.Mutex
+	size   int
+	series map[string]*historyRing
+}
+
+var bgpHistory = &prefixHistory{
+	series: map[string]*historyRing{},
+}
+
+// Record the route counts of the protocols. The series of
+// protocols, which no longer exist, are removed.
+func (h *prefixHistory) record(now time.Time, protocols bird.Parsed) {
+	h.Lock()
+	defer h.Unlock()
+
+	for name := range h.series {
+		if _, ok := protocols[name]; !ok {
+			delete(h.series, name)
+		}
+	}
+
+	for name, p := range protocols {
+		protocol, ok := parsedMap(p)
+		if !ok {
+			continue
+		}
+		routes, ok := parsedMap(protocol["routes"])
+		if !ok {
+			continue
+		}
+
+		series, ok := h.series[name]
+		if !ok {
+			series = newHistoryRing(h.size)
+			h.series[name] = series
+		}
+		series.add(historySample{time: now, routes: routes})
+	}
+}
+
+// Get the samples of a protocol, oldest first
+func (h *prefixHistory) get(name string) ([]historySample, bool) {
+	h.Lock()
+	defer h.Unlock()
+
+	series, ok := h.series[name]
+	if !ok {
+		return nil, false
+	}
+	return series.list(), true
+}
+
+// CollectPrefixHistory periodically records the route
+// counts of the BGP protocols.
+func CollectPrefixHistory() {
+	bgpHistory.Lock()
+	bgpHistory.size = historySize()
+	bgpHistory.Unlock()
+
+	interval := historyInterval()
+	for {
+		// The collector is not subject to the rate limit, its
+		// load on BIRD is bound by the interval.
+		res, _ := bird.ProtocolsBgp(bird.RunOptions{Exempt: true})
+		if protocols, ok := parsedMap(res["protocols"]); ok && !bird.IsSpecial(res) {
+			bgpHistory.record(time.Now(), protocols)
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+func BgpHistory(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	protocol, err := ValidateProtocolParam(ps.ByName("protocol"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	samples, ok := bgpHistory.get(protocol)
+	if !ok {
+		return ErrorResult(http.StatusNotFound,
+			fmt.Errorf("no history for protocol: %s", protocol))
+	}
+
+	history := make([]bird.Parsed, 0, len(samples))
+	for _, sample := range samples {
+		entry := bird.Parsed{"time": sample.time.Format(time.RFC3339)}
+		for k, v := range sample.routes {
+			entry[k] = v
+		}
+		history = append(history, entry)
+	}
+
+	return bird.Parsed{
+		"protocol": protocol,
+		"interval": int64(historyInterval() / time.Second),
+		"history":  history,
+	}, false
+}
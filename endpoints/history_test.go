@@ -0,0 +1,73 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestHistoryRing(t *testing.T) {
+	ring := newHistoryRing(3)
+	start := time.Unix(0, 0)
+	for i := 0; i < 5; i++ {
+		ring.add(historySample{time: start.Add(time.Duration(i) * time.Minute)})
+	}
+
+	samples := ring.list()
+	if len(samples) != 3 {
+		t.Fatal("Expected 3 samples, got:", len(samples))
+	}
+	for i, sample := range samples {
+		expected := start.Add(time.Duration(i+2) * time.Minute)
+		if !sample.time.Equal(expected) {
+			t.Error("Expected sample at", expected, "got:", sample.time)
+		}
+	}
+}
+
+func TestHistorySize(t *testing.T) {
+	HistoryConf = HistoryConfig{Interval: 30, Retention: 60}
+	defer func() { HistoryConf = HistoryConfig{} }()
+
+	if size := historySize(); size != 120 {
+		t.Error("Expected 120 samples, got:", size)
+	}
+}
+
+func TestBgpHistory(t *testing.T) {
+	history := &prefixHistory{size: 10, series: map[string]*historyRing{}}
+	history.record(time.Now(), bird.Parsed{
+		"R1": bird.Parsed{"routes": bird.Parsed{"imported": int64(10)}},
+		"R2": bird.Parsed{"routes": bird.Parsed{"imported": int64(20)}},
+	})
+	history.record(time.Now(), bird.Parsed{
+		"R1": bird.Parsed{"routes": bird.Parsed{"imported": int64(12)}},
+	})
+
+	if _, ok := history.get("R2"); ok {
+		t.Error("Expected the series of R2 to be removed")
+	}
+
+	saved := bgpHistory
+	bgpHistory = history
+	defer func() { bgpHistory = saved }()
+
+	r := httptest.NewRequest("GET", "/protocols/bgp/R1/history", nil)
+	res, _ := BgpHistory(r, httprouter.Params{{Key: "protocol", Value: "R1"}}, bird.RunOptions{UseCache: true})
+	samples, ok := res["history"].([]bird.Parsed)
+	if !ok || len(samples) != 2 {
+		t.Fatal("Expected 2 samples, got:", res["history"])
+	}
+	if samples[1]["imported"] != int64(12) {
+		t.Error("Expected 12 imported routes, got:", samples[1]["imported"])
+	}
+
+	res, _ = BgpHistory(r, httprouter.Params{{Key: "protocol", Value: "R3"}}, bird.RunOptions{UseCache: true})
+	if res[errorStatusKey] != http.StatusNotFound {
+		t.Error("Expected status 404 for unknown protocol, got:", res)
+	}
+}
@@ -0,0 +1,12 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+func Interfaces(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	return bird.Interfaces(opts)
+}
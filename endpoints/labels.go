@@ -0,0 +1,58 @@
+package endpoints
+
+import (
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+var LabelsConf LabelsConfig
+
+// Get the label of a route's peer. Peers are labeled by
+// the address the route was learnt from or the protocol.
+func peerLabel(route bird.Parsed) (string, bool) {
+	for _, key := range []string{"learnt_from", "from_protocol"} {
+		peer, _ := route[key].(string)
+		if label, ok := LabelsConf.Peers[peer]; ok && peer != "" {
+			return label, true
+		}
+	}
+	return "", false
+}
+
+// Annotate the routes in the result with the configured
+// labels of their next hop and peer as next_hop_label
+// and peer_label.
+func labelRoutes(res bird.Parsed) {
+	if len(LabelsConf.NextHops) == 0 && len(LabelsConf.Peers) == 0 {
+		return
+	}
+
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return
+	}
+
+	labeled := make([]bird.Parsed, 0, len(routes))
+	for _, route := range routes {
+		gateway, _ := route["gateway"].(string)
+		nextHopLabel, hasNextHopLabel := LabelsConf.NextHops[gateway]
+		peerLabel, hasPeerLabel := peerLabel(route)
+		if !hasNextHopLabel && !hasPeerLabel {
+			labeled = append(labeled, route)
+			continue
+		}
+
+		// Routes are shared with the cache
+		copied := bird.Parsed{}
+		for k, v := range route {
+			copied[k] = v
+		}
+		if hasNextHopLabel {
+			copied["next_hop_label"] = nextHopLabel
+		}
+		if hasPeerLabel {
+			copied["peer_label"] = peerLabel
+		}
+		labeled = append(labeled, copied)
+	}
+	res["routes"] = labeled
+}
@@ -0,0 +1,47 @@
+package endpoints
+
+import (
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestLabelRoutes(t *testing.T) {
+	routes := []bird.Parsed{
+		{"network": "10.0.0.0/8", "gateway": "192.0.2.1", "learnt_from": "192.0.2.42"},
+		{"network": "10.1.0.0/16", "gateway": "192.0.2.2", "from_protocol": "R192_23"},
+		{"network": "10.2.0.0/16", "gateway": "192.0.2.3"},
+	}
+
+	// Without labels, nothing is annotated
+	res := bird.Parsed{"routes": routes}
+	labelRoutes(res)
+	if _, ok := res["routes"].([]bird.Parsed)[0]["next_hop_label"]; ok {
+		t.Error("Expected no labels without configuration")
+	}
+
+	LabelsConf = LabelsConfig{
+		NextHops: map[string]string{"192.0.2.1": "edge-1"},
+		Peers: map[string]string{
+			"192.0.2.42": "Transit",
+			"R192_23":    "Peering",
+		},
+	}
+	defer func() { LabelsConf = LabelsConfig{} }()
+
+	res = bird.Parsed{"routes": routes}
+	labelRoutes(res)
+	labeled := res["routes"].([]bird.Parsed)
+	if labeled[0]["next_hop_label"] != "edge-1" || labeled[0]["peer_label"] != "Transit" {
+		t.Error("Unexpected labels:", labeled[0])
+	}
+	if labeled[1]["peer_label"] != "Peering" {
+		t.Error("Expected peer label by protocol, got:", labeled[1])
+	}
+	if _, ok := labeled[2]["next_hop_label"]; ok {
+		t.Error("Expected no label for unknown next hop")
+	}
+	if _, ok := routes[0]["next_hop_label"]; ok {
+		t.Error("Expected the original routes not to be modified")
+	}
+}
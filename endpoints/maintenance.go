@@ -0,0 +1,92 @@
+package endpoints
+
+// Maintenance mode, in which data endpoints except the
+// status are unavailable
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultMaintenanceMessage = "birdwatcher is in maintenance mode"
+
+// The maintenance mode is either set through the management
+// endpoint or by the presence of the maintenance file.
+var maintenance struct {
+	sync.RWMutex
+	enabled bool
+	message string
+
+	file        bool
+	fileMessage string
+}
+
+// SetMaintenance enables or disables the maintenance mode.
+// The message is returned with the responses, a default
+// message is used if empty.
+func SetMaintenance(enabled bool, message string) {
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+
+	maintenance.Lock()
+	defer maintenance.Unlock()
+	maintenance.enabled = enabled
+	maintenance.message = message
+}
+
+// Check if in maintenance mode and get the message.
+// The maintenance file is only checked if the mode
+// was not set through the management endpoint.
+func maintenanceStatus() (bool, string) {
+	maintenance.RLock()
+	defer maintenance.RUnlock()
+	if maintenance.enabled {
+		return true, maintenance.message
+	}
+	return maintenance.file, maintenance.fileMessage
+}
+
+// The status is served in maintenance mode, so monitoring
+// can tell the maintenance from a failure.
+func isMaintenanceExempt(req *http.Request) bool {
+	module, _ := req.Context().Value(moduleContextKey{}).(string)
+	return module == "status"
+}
+
+// Check for the maintenance file. The content of the
+// file is used as message.
+func checkMaintenanceFile(filename string) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		log.Println("Could not read maintenance file:", err)
+	}
+	present := err == nil
+
+	message := strings.TrimSpace(string(content))
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+
+	maintenance.Lock()
+	defer maintenance.Unlock()
+	if present != maintenance.file {
+		log.Println("Maintenance file", filename, "present:", present)
+	}
+	maintenance.file = present
+	maintenance.fileMessage = message
+}
+
+// WatchMaintenanceFile periodically checks if the maintenance
+// file is present, which enables the maintenance mode.
+func WatchMaintenanceFile() {
+	for {
+		checkMaintenanceFile(Conf.MaintenanceFile)
+		time.Sleep(5 * time.Second)
+	}
+}
@@ -0,0 +1,82 @@
+package endpoints
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestEndpointMaintenance(t *testing.T) {
+	handle := Endpoint(func(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+		return bird.Parsed{"routes": []bird.Parsed{}}, false
+	})
+
+	SetMaintenance(true, "BIRD upgrade")
+	w := httptest.NewRecorder()
+	handle(w, httptest.NewRequest("GET", "/routes/table/master", nil), nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Error("Expected status 503, got:", w.Code)
+	}
+	if w.Body.String() != `{"error":"BIRD upgrade"}` {
+		t.Error("Unexpected body:", w.Body.String())
+	}
+
+	SetMaintenance(false, "")
+	w = httptest.NewRecorder()
+	handle(w, httptest.NewRequest("GET", "/routes/table/master", nil), nil)
+	if w.Code != http.StatusOK {
+		t.Error("Expected status 200, got:", w.Code)
+	}
+}
+
+func TestMaintenanceFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "birdwatcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "maintenance")
+	defer checkMaintenanceFile(filename)
+
+	checkMaintenanceFile(filename)
+	if ok, _ := maintenanceStatus(); ok {
+		t.Error("Expected no maintenance without the file")
+	}
+
+	if err := ioutil.WriteFile(filename, []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+	checkMaintenanceFile(filename)
+	if ok, message := maintenanceStatus(); !ok || message != defaultMaintenanceMessage {
+		t.Error("Expected maintenance with the default message, got:", ok, message)
+	}
+
+	os.Remove(filename)
+	checkMaintenanceFile(filename)
+	if ok, _ := maintenanceStatus(); ok {
+		t.Error("Expected no maintenance after the file was removed")
+	}
+}
+
+func TestStatusMaintenance(t *testing.T) {
+	defer helperBirdc("status1.sample")()
+	handle := WithModule("status", Endpoint(Status))
+
+	SetMaintenance(true, "BIRD upgrade")
+	defer SetMaintenance(false, "")
+	w := httptest.NewRecorder()
+	handle(w, httptest.NewRequest("GET", "/status", nil), nil)
+	if w.Code != http.StatusOK {
+		t.Fatal("Expected status 200 in maintenance mode, got:", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"maintenance":{"message":"BIRD upgrade"}`) {
+		t.Error("Expected the maintenance to be reported, got:", w.Body.String())
+	}
+}
@@ -0,0 +1,285 @@
+package endpoints
+
+// Conversion of the results, decoded from JSON, to the
+// messages of the gRPC interface. Missing or mistyped
+// values are left empty.
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/alice-lg/birdwatcher/pb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func jsonString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
+
+func jsonInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return int64(f)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+// Get an optional number, which is nil if not set
+func jsonOptionalInt(value interface{}) *wrapperspb.Int64Value {
+	if _, ok := value.(json.Number); !ok {
+		return nil
+	}
+	return wrapperspb.Int64(jsonInt(value))
+}
+
+func jsonObject(value interface{}) map[string]interface{} {
+	v, _ := value.(map[string]interface{})
+	return v
+}
+
+func jsonStrings(value interface{}) []string {
+	list, _ := value.([]interface{})
+	res := make([]string, 0, len(list))
+	for _, v := range list {
+		res = append(res, jsonString(v))
+	}
+	return res
+}
+
+func jsonInts(value interface{}) []int64 {
+	list, _ := value.([]interface{})
+	res := make([]int64, 0, len(list))
+	for _, v := range list {
+		res = append(res, jsonInt(v))
+	}
+	return res
+}
+
+func statusMessage(value interface{}) *pb.Status {
+	status := jsonObject(value)
+	if status == nil {
+		return nil
+	}
+	return &pb.Status{
+		Version:       jsonString(status["version"]),
+		RouterId:      jsonString(status["router_id"]),
+		Hostname:      jsonString(status["hostname"]),
+		CurrentServer: jsonString(status["current_server"]),
+		LastReboot:    jsonString(status["last_reboot"]),
+		LastReconfig:  jsonString(status["last_reconfig"]),
+		Message:       jsonString(status["message"]),
+	}
+}
+
+func protocolMessages(value interface{}) map[string]*pb.Protocol {
+	protocols := map[string]*pb.Protocol{}
+	for name, v := range jsonObject(value) {
+		if p := jsonObject(v); p != nil {
+			protocols[name] = protocolMessage(p)
+		}
+	}
+	return protocols
+}
+
+func protocolMessage(p map[string]interface{}) *pb.Protocol {
+	protocol := &pb.Protocol{
+		Protocol:          jsonString(p["protocol"]),
+		BirdProtocol:      jsonString(p["bird_protocol"]),
+		Table:             jsonString(p["table"]),
+		State:             jsonString(p["state"]),
+		StateChanged:      jsonString(p["state_changed"]),
+		StateChangedAt:    jsonString(p["state_changed_at"]),
+		UptimeSeconds:     jsonInt(p["uptime_seconds"]),
+		Connection:        jsonString(p["connection"]),
+		PeerTable:         jsonString(p["peer_table"]),
+		Description:       jsonString(p["description"]),
+		BgpState:          jsonString(p["bgp_state"]),
+		NeighborAddress:   jsonString(p["neighbor_address"]),
+		NeighborAs:        jsonInt(p["neighbor_as"]),
+		NeighborId:        jsonString(p["neighbor_id"]),
+		LocalAs:           jsonInt(p["local_as"]),
+		SourceAddress:     jsonString(p["source_address"]),
+		LastError:         jsonString(p["last_error"]),
+		HoldTime:          jsonOptionalInt(p["hold_time"]),
+		Keepalive:         jsonOptionalInt(p["keepalive"]),
+		LocalRole:         jsonString(p["local_role"]),
+		RemoteRole:        jsonString(p["remote_role"]),
+		Preference:        jsonInt(p["preference"]),
+		ImportFilter:      jsonString(p["import_filter"]),
+		ExportFilter:      jsonString(p["export_filter"]),
+		ImportLimit:       jsonInt(p["import_limit"]),
+		ImportLimitAction: jsonString(p["import_limit_action"]),
+		Routes:            routeCountsMessage(p["routes"]),
+		RouteChanges:      routeChangesMessage(p["route_changes"]),
+	}
+
+	if channels := jsonObject(p["channels"]); len(channels) > 0 {
+		protocol.Channels = map[string]*pb.Channel{}
+		for name, v := range channels {
+			c := jsonObject(v)
+			if c == nil {
+				continue
+			}
+			protocol.Channels[name] = &pb.Channel{
+				State:             jsonString(c["state"]),
+				Table:             jsonString(c["table"]),
+				Preference:        jsonInt(c["preference"]),
+				ImportFilter:      jsonString(c["import_filter"]),
+				ExportFilter:      jsonString(c["export_filter"]),
+				ImportLimit:       jsonInt(c["import_limit"]),
+				ImportLimitAction: jsonString(c["import_limit_action"]),
+				Routes:            routeCountsMessage(c["routes"]),
+				RouteChanges:      routeChangesMessage(c["route_changes"]),
+			}
+		}
+	}
+
+	return protocol
+}
+
+func routeCountsMessage(value interface{}) *pb.RouteCounts {
+	routes := jsonObject(value)
+	if routes == nil {
+		return nil
+	}
+	return &pb.RouteCounts{
+		Imported:  jsonInt(routes["imported"]),
+		Filtered:  jsonInt(routes["filtered"]),
+		Exported:  jsonInt(routes["exported"]),
+		Preferred: jsonInt(routes["preferred"]),
+		Accepted:  jsonInt(routes["accepted"]),
+	}
+}
+
+func routeChangesMessage(value interface{}) *pb.RouteChanges {
+	changes := jsonObject(value)
+	if changes == nil {
+		return nil
+	}
+	return &pb.RouteChanges{
+		ImportUpdates:   routeChangeCountsMessage(changes["import_updates"]),
+		ImportWithdraws: routeChangeCountsMessage(changes["import_withdraws"]),
+		ExportUpdates:   routeChangeCountsMessage(changes["export_updates"]),
+		ExportWithdraws: routeChangeCountsMessage(changes["export_withdraws"]),
+	}
+}
+
+func routeChangeCountsMessage(value interface{}) *pb.RouteChangeCounts {
+	counts := jsonObject(value)
+	if counts == nil {
+		return nil
+	}
+	return &pb.RouteChangeCounts{
+		Received: jsonOptionalInt(counts["received"]),
+		Rejected: jsonOptionalInt(counts["rejected"]),
+		Filtered: jsonOptionalInt(counts["filtered"]),
+		Ignored:  jsonOptionalInt(counts["ignored"]),
+		Accepted: jsonOptionalInt(counts["accepted"]),
+	}
+}
+
+func routeMessages(value interface{}) []*pb.Route {
+	list, _ := value.([]interface{})
+	routes := make([]*pb.Route, 0, len(list))
+	for _, v := range list {
+		if r := jsonObject(v); r != nil {
+			routes = append(routes, routeMessage(r))
+		}
+	}
+	return routes
+}
+
+func routeMessage(r map[string]interface{}) *pb.Route {
+	route := &pb.Route{
+		Id:                  jsonInt(r["id"]),
+		Network:             jsonString(r["network"]),
+		FromProtocol:        jsonString(r["from_protocol"]),
+		Table:               jsonString(r["table"]),
+		Interface:           jsonString(r["interface"]),
+		Gateway:             jsonString(r["gateway"]),
+		BgpNextHop:          jsonString(r["bgp_next_hop"]),
+		BgpNextHopLinkLocal: jsonString(r["bgp_next_hop_link_local"]),
+		RouteDistinguisher:  jsonString(r["route_distinguisher"]),
+		MplsLabels:          jsonInts(r["mpls_labels"]),
+		Metric:              jsonInt(r["metric"]),
+		Preference:          jsonInt(r["preference"]),
+		IgpMetric:           jsonOptionalInt(r["igp_metric"]),
+		Age:                 jsonString(r["age"]),
+		LearntFrom:          jsonString(r["learnt_from"]),
+		Type:                jsonStrings(r["type"]),
+	}
+	route.Primary, _ = r["primary"].(bool)
+
+	hops, _ := r["next_hops"].([]interface{})
+	for _, v := range hops {
+		hop := jsonObject(v)
+		if hop == nil {
+			continue
+		}
+		route.NextHops = append(route.NextHops, &pb.NextHop{
+			Gateway:    jsonString(hop["gateway"]),
+			Interface:  jsonString(hop["interface"]),
+			MplsLabels: jsonInts(hop["mpls_labels"]),
+			Weight:     jsonInt(hop["weight"]),
+		})
+	}
+
+	if bgp := jsonObject(r["bgp"]); bgp != nil {
+		route.Bgp = &pb.Bgp{
+			Origin:           jsonString(bgp["origin"]),
+			AsPath:           jsonStrings(bgp["as_path"]),
+			NextHop:          jsonString(bgp["next_hop"]),
+			Med:              jsonString(bgp["med"]),
+			LocalPref:        jsonString(bgp["local_pref"]),
+			Communities:      communityMessages(bgp["communities"]),
+			LargeCommunities: communityMessages(bgp["large_communities"]),
+			RouteTargets:     jsonStrings(bgp["route_targets"]),
+			MplsLabelStack:   jsonInts(bgp["mpls_label_stack"]),
+		}
+		communities, _ := bgp["ext_communities"].([]interface{})
+		for _, c := range communities {
+			route.Bgp.ExtCommunities = append(route.Bgp.ExtCommunities,
+				&pb.ExtCommunity{Values: jsonStrings(c)})
+		}
+	}
+
+	if ospf := jsonObject(r["ospf"]); ospf != nil {
+		route.Ospf = &pb.Ospf{
+			Metric1:  jsonInt(ospf["metric1"]),
+			Metric2:  jsonInt(ospf["metric2"]),
+			Tag:      jsonString(ospf["tag"]),
+			RouterId: jsonString(ospf["router_id"]),
+		}
+	}
+
+	if reason := jsonObject(r["noexport_reason"]); reason != nil {
+		route.NoexportReason = &pb.NoexportReason{
+			Protocol: jsonString(reason["protocol"]),
+			Filter:   jsonString(reason["filter"]),
+		}
+	}
+
+	return route
+}
+
+func communityMessages(value interface{}) []*pb.Community {
+	list, _ := value.([]interface{})
+	communities := make([]*pb.Community, 0, len(list))
+	for _, c := range list {
+		communities = append(communities, &pb.Community{Values: jsonInts(c)})
+	}
+	return communities
+}
@@ -0,0 +1,35 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/alice-lg/birdwatcher/metrics"
+	"github.com/julienschmidt/httprouter"
+)
+
+var requests = metrics.NewCounter(
+	"birdwatcher_requests_total",
+	"Requests to the endpoints by module, table and protocol")
+
+// Count a request to the module of the endpoint. The
+// table and protocol labels are guarded, as there
+// might be thousands of them.
+func countRequest(r *http.Request, ps httprouter.Params) {
+	module, _ := r.Context().Value(moduleContextKey{}).(string)
+	requests.Inc(
+		"module", module,
+		"table", ps.ByName("table"),
+		"protocol", ps.ByName("protocol"))
+}
+
+// Metrics serves the metrics in the Prometheus
+// text exposition format.
+func Metrics(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if err := CheckAccess(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	metrics.WritePrometheus(w)
+}
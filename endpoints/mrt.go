@@ -0,0 +1,336 @@
+package endpoints
+
+// Serialization of routes as MRT TABLE_DUMP_V2 (RFC 6396).
+// The format is documented in docs/mrt.md.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+// MRT types and subtypes
+const (
+	mrtTableDumpV2        = 13
+	mrtPeerIndexTable     = 1
+	mrtRibIPv4Unicast     = 2
+	mrtRibIPv6Unicast     = 4
+	mrtPeerTypeIPv6       = 0x01
+	mrtPeerTypeAS4        = 0x02
+	mrtViewName           = "birdwatcher"
+	mrtAttrOptional       = 0x80
+	mrtAttrTransitive     = 0x40
+	mrtAttrExtendedLen    = 0x10
+	mrtAttrOrigin         = 1
+	mrtAttrAsPath         = 2
+	mrtAttrNextHop        = 3
+	mrtAttrMed            = 4
+	mrtAttrLocalPref      = 5
+	mrtAttrCommunities    = 8
+	mrtAttrMpReachNlri    = 14
+	mrtAttrLargeCommunity = 32
+	mrtAsSequence         = 2
+)
+
+// Check if MRT is accepted by the client. Only an
+// explicit application/mrt selects the format.
+func acceptsMrt(req *http.Request) bool {
+	for _, mediaType := range acceptedMediaTypes(req) {
+		if mediaType == "application/mrt" {
+			return true
+		}
+	}
+	return false
+}
+
+// A peer of the peer index table
+type mrtPeer struct {
+	addr net.IP
+	as   uint32
+}
+
+// Get the peer of a route, the address the route was
+// learnt from and the first AS of its path.
+func routeMrtPeer(route bird.Parsed) mrtPeer {
+	peer := mrtPeer{addr: net.IPv4zero}
+	for _, key := range []string{"learnt_from", "bgp_next_hop", "gateway"} {
+		addr, _ := route[key].(string)
+		if ip := net.ParseIP(addr); ip != nil {
+			peer.addr = ip
+			break
+		}
+	}
+	if path := routeAsPath(route); len(path) > 0 {
+		peer.as = path[0]
+	}
+	return peer
+}
+
+func (p mrtPeer) key() string {
+	return p.addr.String() + " " + strconv.FormatUint(uint64(p.as), 10)
+}
+
+// Get the AS path of a route. Sets and other
+// non numeric elements are skipped.
+func routeAsPath(route bird.Parsed) []uint32 {
+	bgp, ok := parsedMap(route["bgp"])
+	if !ok {
+		return nil
+	}
+
+	elements := []string{}
+	switch path := bgp["as_path"].(type) {
+	case []string:
+		elements = path
+	case []interface{}:
+		for _, e := range path {
+			s, _ := e.(string)
+			elements = append(elements, s)
+		}
+	}
+
+	path := []uint32{}
+	for _, e := range elements {
+		as, err := strconv.ParseUint(e, 10, 32)
+		if err != nil {
+			continue
+		}
+		path = append(path, uint32(as))
+	}
+	return path
+}
+
+// Write the MRT common header and the record
+func writeMrtRecord(w io.Writer, timestamp uint32, subtype uint16, record []byte) error {
+	header := make([]byte, 12)
+	binary.BigEndian.PutUint32(header[0:], timestamp)
+	binary.BigEndian.PutUint16(header[4:], mrtTableDumpV2)
+	binary.BigEndian.PutUint16(header[6:], subtype)
+	binary.BigEndian.PutUint32(header[8:], uint32(len(record)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(record)
+	return err
+}
+
+// Encode a path attribute, using the extended
+// length if the data exceeds 255 bytes.
+func mrtAttr(buf *bytes.Buffer, flags byte, attrType byte, data []byte) {
+	if len(data) > 255 {
+		buf.Write([]byte{flags | mrtAttrExtendedLen, attrType})
+		binary.Write(buf, binary.BigEndian, uint16(len(data)))
+	} else {
+		buf.Write([]byte{flags, attrType, byte(len(data))})
+	}
+	buf.Write(data)
+}
+
+// Encode the BGP path attributes of a route
+func mrtRouteAttrs(route bird.Parsed, ipv6 bool) []byte {
+	buf := &bytes.Buffer{}
+	bgp, _ := parsedMap(route["bgp"])
+
+	origin, _ := bgp["origin"].(string)
+	switch strings.ToLower(origin) {
+	case "igp", "i":
+		mrtAttr(buf, mrtAttrTransitive, mrtAttrOrigin, []byte{0})
+	case "egp", "e":
+		mrtAttr(buf, mrtAttrTransitive, mrtAttrOrigin, []byte{1})
+	case "incomplete", "?":
+		mrtAttr(buf, mrtAttrTransitive, mrtAttrOrigin, []byte{2})
+	}
+
+	// The path is split into segments of up to 255 ASNs
+	path := routeAsPath(route)
+	segments := &bytes.Buffer{}
+	for len(path) > 0 {
+		n := len(path)
+		if n > 255 {
+			n = 255
+		}
+		segments.Write([]byte{mrtAsSequence, byte(n)})
+		for _, as := range path[:n] {
+			binary.Write(segments, binary.BigEndian, as)
+		}
+		path = path[n:]
+	}
+	mrtAttr(buf, mrtAttrTransitive, mrtAttrAsPath, segments.Bytes())
+
+	nextHops := []net.IP{}
+	for _, key := range []string{"bgp_next_hop", "bgp_next_hop_link_local"} {
+		addr, _ := route[key].(string)
+		if ip := net.ParseIP(addr); ip != nil {
+			nextHops = append(nextHops, ip)
+		}
+	}
+	if len(nextHops) == 0 {
+		gateway, _ := route["gateway"].(string)
+		if ip := net.ParseIP(gateway); ip != nil {
+			nextHops = append(nextHops, ip)
+		}
+	}
+	if len(nextHops) > 0 {
+		if ipv6 {
+			// MP_REACH_NLRI is abbreviated to the next hops
+			data := []byte{0}
+			for _, ip := range nextHops {
+				data = append(data, ip.To16()...)
+			}
+			data[0] = byte(len(data) - 1)
+			mrtAttr(buf, mrtAttrOptional, mrtAttrMpReachNlri, data)
+		} else if ip := nextHops[0].To4(); ip != nil {
+			mrtAttr(buf, mrtAttrTransitive, mrtAttrNextHop, ip)
+		}
+	}
+
+	for _, attr := range []struct {
+		key      string
+		flags    byte
+		attrType byte
+	}{
+		{"med", mrtAttrOptional, mrtAttrMed},
+		{"local_pref", mrtAttrTransitive, mrtAttrLocalPref},
+	} {
+		value, _ := bgp[attr.key].(string)
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+		if err != nil {
+			continue
+		}
+		data := make([]byte, 4)
+		binary.BigEndian.PutUint32(data, uint32(n))
+		mrtAttr(buf, attr.flags, attr.attrType, data)
+	}
+
+	if communities := routeCommunities(bgp, "communities"); len(communities) > 0 {
+		data := &bytes.Buffer{}
+		for _, c := range communities {
+			if len(c) == 2 {
+				binary.Write(data, binary.BigEndian, uint16(c[0]))
+				binary.Write(data, binary.BigEndian, uint16(c[1]))
+			}
+		}
+		mrtAttr(buf, mrtAttrOptional|mrtAttrTransitive, mrtAttrCommunities, data.Bytes())
+	}
+	if communities := routeCommunities(bgp, "large_communities"); len(communities) > 0 {
+		data := &bytes.Buffer{}
+		for _, c := range communities {
+			if len(c) == 3 {
+				for _, v := range c {
+					binary.Write(data, binary.BigEndian, uint32(v))
+				}
+			}
+		}
+		mrtAttr(buf, mrtAttrOptional|mrtAttrTransitive, mrtAttrLargeCommunity, data.Bytes())
+	}
+
+	return buf.Bytes()
+}
+
+// A RIB record holds all routes of a network
+type mrtRib struct {
+	network *net.IPNet
+	routes  []bird.Parsed
+}
+
+// Group the routes by network, in the order
+// the networks first appear.
+func mrtRibs(routes []bird.Parsed) []*mrtRib {
+	ribs := []*mrtRib{}
+	byNetwork := map[string]*mrtRib{}
+	for _, route := range routes {
+		network, _ := route["network"].(string)
+		_, n, err := net.ParseCIDR(network)
+		if err != nil {
+			continue
+		}
+		rib, ok := byNetwork[n.String()]
+		if !ok {
+			rib = &mrtRib{network: n}
+			byNetwork[n.String()] = rib
+			ribs = append(ribs, rib)
+		}
+		rib.routes = append(rib.routes, route)
+	}
+	return ribs
+}
+
+// writeMrt writes the routes as a peer index table
+// followed by a RIB record for each network.
+func writeMrt(w io.Writer, routes []bird.Parsed, now time.Time) error {
+	timestamp := uint32(now.Unix())
+	ribs := mrtRibs(routes)
+
+	peers := []mrtPeer{}
+	peerIndex := map[string]uint16{}
+	for _, rib := range ribs {
+		for _, route := range rib.routes {
+			peer := routeMrtPeer(route)
+			if _, ok := peerIndex[peer.key()]; !ok {
+				peerIndex[peer.key()] = uint16(len(peers))
+				peers = append(peers, peer)
+			}
+		}
+	}
+
+	index := &bytes.Buffer{}
+	index.Write(net.IPv4zero.To4()) // collector BGP ID
+	binary.Write(index, binary.BigEndian, uint16(len(mrtViewName)))
+	index.WriteString(mrtViewName)
+	binary.Write(index, binary.BigEndian, uint16(len(peers)))
+	for _, peer := range peers {
+		if ip := peer.addr.To4(); ip != nil {
+			index.WriteByte(mrtPeerTypeAS4)
+			index.Write(ip) // peer BGP ID
+			index.Write(ip)
+		} else {
+			index.WriteByte(mrtPeerTypeAS4 | mrtPeerTypeIPv6)
+			index.Write(net.IPv4zero.To4())
+			index.Write(peer.addr.To16())
+		}
+		binary.Write(index, binary.BigEndian, peer.as)
+	}
+	if err := writeMrtRecord(w, timestamp, mrtPeerIndexTable, index.Bytes()); err != nil {
+		return err
+	}
+
+	for seq, rib := range ribs {
+		prefix := rib.network.IP.To4()
+		subtype := uint16(mrtRibIPv4Unicast)
+		if prefix == nil {
+			prefix = rib.network.IP.To16()
+			subtype = mrtRibIPv6Unicast
+		}
+		length, _ := rib.network.Mask.Size()
+
+		record := &bytes.Buffer{}
+		binary.Write(record, binary.BigEndian, uint32(seq))
+		record.WriteByte(byte(length))
+		record.Write(prefix[:(length+7)/8])
+		binary.Write(record, binary.BigEndian, uint16(len(rib.routes)))
+		for _, route := range rib.routes {
+			originated := timestamp
+			if since := routeSince(route); !since.IsZero() {
+				originated = uint32(since.Unix())
+			}
+			attrs := mrtRouteAttrs(route, subtype == mrtRibIPv6Unicast)
+
+			binary.Write(record, binary.BigEndian, peerIndex[routeMrtPeer(route).key()])
+			binary.Write(record, binary.BigEndian, originated)
+			binary.Write(record, binary.BigEndian, uint16(len(attrs)))
+			record.Write(attrs)
+		}
+		if err := writeMrtRecord(w, timestamp, subtype, record.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package endpoints
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+// Read the records of a MRT dump as subtype and body
+func readMrtRecords(t *testing.T, dump []byte) ([]uint16, [][]byte) {
+	subtypes := []uint16{}
+	records := [][]byte{}
+	for len(dump) > 0 {
+		if len(dump) < 12 {
+			t.Fatal("Truncated MRT header")
+		}
+		if recordType := binary.BigEndian.Uint16(dump[4:]); recordType != mrtTableDumpV2 {
+			t.Fatal("Unexpected MRT type:", recordType)
+		}
+		length := binary.BigEndian.Uint32(dump[8:])
+		subtypes = append(subtypes, binary.BigEndian.Uint16(dump[6:]))
+		records = append(records, dump[12:12+length])
+		dump = dump[12+length:]
+	}
+	return subtypes, records
+}
+
+func TestWriteMrt(t *testing.T) {
+	routes := []bird.Parsed{
+		{
+			"network":      "10.0.0.0/8",
+			"gateway":      "192.0.2.1",
+			"learnt_from":  "192.0.2.1",
+			"bgp_next_hop": "192.0.2.1",
+			"bgp": bird.Parsed{
+				"origin":      "IGP",
+				"as_path":     []string{"64500", "64501"},
+				"local_pref":  "100",
+				"communities": [][]int64{{64500, 42}},
+			},
+		},
+		{
+			"network":     "2001:db8::/32",
+			"gateway":     "2001:db8::1",
+			"learnt_from": "2001:db8::1",
+			"bgp":         bird.Parsed{"as_path": []string{"64502"}},
+		},
+		{
+			"network":     "10.0.0.0/8",
+			"gateway":     "192.0.2.2",
+			"learnt_from": "192.0.2.2",
+			"bgp":         bird.Parsed{"as_path": []string{"64503"}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeMrt(buf, routes, time.Unix(1600000000, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	subtypes, records := readMrtRecords(t, buf.Bytes())
+	expected := []uint16{mrtPeerIndexTable, mrtRibIPv4Unicast, mrtRibIPv6Unicast}
+	if len(subtypes) != len(expected) {
+		t.Fatal("Expected", len(expected), "records, got:", subtypes)
+	}
+	for i := range expected {
+		if subtypes[i] != expected[i] {
+			t.Error("Expected subtype", expected[i], "got:", subtypes[i])
+		}
+	}
+
+	// Peer index table: BGP ID, view name and peers
+	index := records[0]
+	nameLen := int(binary.BigEndian.Uint16(index[4:]))
+	if name := string(index[6 : 6+nameLen]); name != mrtViewName {
+		t.Error("Unexpected view name:", name)
+	}
+	if peers := binary.BigEndian.Uint16(index[6+nameLen:]); peers != 3 {
+		t.Error("Expected 3 peers, got:", peers)
+	}
+
+	// RIB of 10.0.0.0/8 with both routes
+	rib := records[1]
+	if rib[4] != 8 || rib[5] != 10 {
+		t.Error("Unexpected prefix:", rib[4:6])
+	}
+	if entries := binary.BigEndian.Uint16(rib[6:]); entries != 2 {
+		t.Error("Expected 2 entries, got:", entries)
+	}
+	attrLen := binary.BigEndian.Uint16(rib[8+6:])
+	attrs := rib[8+8 : 8+8+int(attrLen)]
+	asPath := []byte{mrtAttrTransitive, mrtAttrAsPath, 10, mrtAsSequence, 2,
+		0, 0, 0xfb, 0xf4, 0, 0, 0xfb, 0xf5}
+	if !bytes.Contains(attrs, asPath) {
+		t.Errorf("Expected AS path in attributes: % x", attrs)
+	}
+	community := []byte{mrtAttrOptional | mrtAttrTransitive, mrtAttrCommunities, 4, 0xfb, 0xf4, 0, 42}
+	if !bytes.Contains(attrs, community) {
+		t.Errorf("Expected community in attributes: % x", attrs)
+	}
+	nextHop := []byte{mrtAttrTransitive, mrtAttrNextHop, 4, 192, 0, 2, 1}
+	if !bytes.Contains(attrs, nextHop) {
+		t.Errorf("Expected next hop in attributes: % x", attrs)
+	}
+
+	// RIB of 2001:db8::/32 with MP_REACH_NLRI next hop
+	rib = records[2]
+	if rib[4] != 32 || !bytes.Equal(rib[5:9], []byte{0x20, 0x01, 0x0d, 0xb8}) {
+		t.Error("Unexpected prefix:", rib[4:9])
+	}
+	if !bytes.Contains(rib, []byte{mrtAttrOptional, mrtAttrMpReachNlri, 17, 16}) {
+		t.Errorf("Expected MP_REACH_NLRI in attributes: % x", rib)
+	}
+}
+
+func TestAcceptsMrt(t *testing.T) {
+	r := httptest.NewRequest("GET", "/routes/table/master", nil)
+	r.Header.Set("Accept", "application/json, application/mrt;q=0.5")
+	if !acceptsMrt(r) {
+		t.Error("Expected MRT to be accepted")
+	}
+	r.Header.Set("Accept", "*/*")
+	if acceptsMrt(r) {
+		t.Error("Expected MRT only if requested explicitly")
+	}
+	r.Header.Set("Accept", "application/json, application/mrt; q=0")
+	if acceptsMrt(r) {
+		t.Error("Expected MRT with q=0 not to be accepted")
+	}
+}
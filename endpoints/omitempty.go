@@ -0,0 +1,82 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+// Check if empty fields are omitted from the routes, as
+// requested with the omitempty query parameter. Defaults
+// to the omit_empty setting of the server.
+func queryOmitEmpty(r *http.Request) (bool, error) {
+	values := r.URL.Query()["omitempty"]
+	switch {
+	case len(values) == 0:
+		return Conf.OmitEmpty, nil
+	case len(values) > 1:
+		return false, fmt.Errorf("need omitempty as single query parameter")
+	}
+
+	switch values[0] {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("omitempty must be either 'true' or 'false'")
+}
+
+// Empty values are null, empty strings, lists and
+// objects. Numbers are kept, as zero is a valid
+// value e.g. for the MED.
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Copy the fields of a route which are not empty,
+// including those of nested objects.
+func omitEmptyFields(route bird.Parsed) bird.Parsed {
+	res := bird.Parsed{}
+	for k, v := range route {
+		if nested, ok := parsedMap(v); ok {
+			v = omitEmptyFields(nested)
+		}
+		if isEmptyValue(v) {
+			continue
+		}
+		res[k] = v
+	}
+	return res
+}
+
+// Remove the empty fields from the routes in the
+// result, if requested.
+func omitEmptyRouteFields(r *http.Request, res bird.Parsed) {
+	if omit, err := queryOmitEmpty(r); err != nil || !omit {
+		return
+	}
+
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return
+	}
+
+	trimmed := make([]bird.Parsed, 0, len(routes))
+	for _, route := range routes {
+		trimmed = append(trimmed, omitEmptyFields(route))
+	}
+	res["routes"] = trimmed
+}
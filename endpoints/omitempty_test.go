@@ -0,0 +1,77 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestOmitEmptyRouteFields(t *testing.T) {
+	route := bird.Parsed{
+		"network":   "10.0.0.0/8",
+		"interface": "",
+		"metric":    int64(0),
+		"primary":   false,
+		"bgp": bird.Parsed{
+			"as_path":           []string{"65001"},
+			"communities":       [][]int64{},
+			"large_communities": []interface{}{},
+			"med":               int64(0),
+		},
+		"mpls_labels": nil,
+	}
+	res := bird.Parsed{"routes": []bird.Parsed{route}}
+
+	r := httptest.NewRequest("GET", "/routes/table/master?omitempty=true", nil)
+	omitEmptyRouteFields(r, res)
+
+	expected := bird.Parsed{
+		"network": "10.0.0.0/8",
+		"metric":  int64(0),
+		"primary": false,
+		"bgp": bird.Parsed{
+			"as_path": []string{"65001"},
+			"med":     int64(0),
+		},
+	}
+	if trimmed := res["routes"].([]bird.Parsed)[0]; !reflect.DeepEqual(trimmed, expected) {
+		t.Error("Expected route:", expected, "got:", trimmed)
+	}
+	if _, ok := route["interface"]; !ok {
+		t.Error("Expected the route not to be modified")
+	}
+
+	// The full route is kept by default
+	res = bird.Parsed{"routes": []bird.Parsed{route}}
+	r = httptest.NewRequest("GET", "/routes/table/master", nil)
+	omitEmptyRouteFields(r, res)
+	if !reflect.DeepEqual(res["routes"].([]bird.Parsed)[0], route) {
+		t.Error("Expected the full route without omitempty")
+	}
+}
+
+func TestQueryOmitEmpty(t *testing.T) {
+	Conf.OmitEmpty = true
+	defer func() { Conf.OmitEmpty = false }()
+
+	tests := []struct {
+		query string
+		omit  bool
+		valid bool
+	}{
+		{"", true, true},
+		{"?omitempty=false", false, true},
+		{"?omitempty=true", true, true},
+		{"?omitempty=yes", false, false},
+		{"?omitempty=true&omitempty=false", false, false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/routes/table/master"+test.query, nil)
+		omit, err := queryOmitEmpty(r)
+		if (err == nil) != test.valid || omit != test.omit {
+			t.Error("Unexpected result for", test.query, "got:", omit, err)
+		}
+	}
+}
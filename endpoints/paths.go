@@ -0,0 +1,53 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+// Route endpoints return all paths of a network unless
+// only the best paths are requested with ?paths=best
+const (
+	pathsAll  = "all"
+	pathsBest = "best"
+)
+
+// Get the paths query parameter. Defaults to all paths.
+func queryPaths(r *http.Request) (string, error) {
+	qs := r.URL.Query()
+	if len(qs["paths"]) == 0 {
+		return pathsAll, nil
+	}
+	if len(qs["paths"]) != 1 {
+		return "", fmt.Errorf("need paths as single query parameter")
+	}
+
+	paths := qs["paths"][0]
+	if paths != pathsAll && paths != pathsBest {
+		return "", fmt.Errorf("paths must be either 'best' or 'all'")
+	}
+	return paths, nil
+}
+
+// Reduce the routes in the result to the primary
+// routes, if only the best paths are requested.
+func selectRoutePaths(r *http.Request, res bird.Parsed) {
+	if paths, _ := queryPaths(r); paths != pathsBest {
+		return
+	}
+
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return
+	}
+
+	selected := make([]bird.Parsed, 0, len(routes))
+	for _, route := range routes {
+		if primary, _ := route["primary"].(bool); primary {
+			selected = append(selected, route)
+		}
+	}
+	res["routes"] = selected
+}
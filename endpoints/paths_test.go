@@ -0,0 +1,36 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestSelectRoutePaths(t *testing.T) {
+	best := bird.Parsed{"network": "10.0.0.0/8", "primary": true}
+	other := bird.Parsed{"network": "10.0.0.0/8", "primary": false}
+
+	res := bird.Parsed{"routes": []bird.Parsed{best, other}}
+	r := httptest.NewRequest("GET", "/routes/table/master", nil)
+	selectRoutePaths(r, res)
+	if len(res["routes"].([]bird.Parsed)) != 2 {
+		t.Error("Expected all paths by default, got:", res["routes"])
+	}
+
+	r = httptest.NewRequest("GET", "/routes/table/master?paths=best", nil)
+	selectRoutePaths(r, res)
+	if !reflect.DeepEqual(res["routes"], []bird.Parsed{best}) {
+		t.Error("Expected only the best path, got:", res["routes"])
+	}
+}
+
+func TestQueryPaths(t *testing.T) {
+	for _, query := range []string{"?paths=some", "?paths=best&paths=all"} {
+		r := httptest.NewRequest("GET", "/routes/table/master"+query, nil)
+		if _, err := queryPaths(r); err == nil {
+			t.Error("Expected an error for", query)
+		}
+	}
+}
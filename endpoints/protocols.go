@@ -1,20 +1,142 @@
 package endpoints
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/alice-lg/birdwatcher/bird"
 	"github.com/julienschmidt/httprouter"
 )
 
-func Protocols(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	return bird.Protocols(useCache)
+func Protocols(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	return bird.Protocols(opts)
 }
 
-func Bgp(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	return bird.ProtocolsBgp(useCache)
+// Get the fraction of the import limit from the
+// near_limit query parameter. Zero if not present.
+func queryNearLimit(r *http.Request) (float64, error) {
+	qs := r.URL.Query()
+	if len(qs["near_limit"]) == 0 {
+		return 0, nil
+	}
+	if len(qs["near_limit"]) > 1 {
+		return 0, fmt.Errorf("need near_limit as single query parameter")
+	}
+	fraction, err := strconv.ParseFloat(qs["near_limit"][0], 64)
+	if err != nil || fraction <= 0 {
+		return 0, fmt.Errorf("near_limit must be a positive fraction of the import limit")
+	}
+	return fraction, nil
 }
 
-func ProtocolsShort(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	return bird.ProtocolsShort(useCache)
+// Select the protocols which imported at least the fraction
+// of their import limit. Protocols without limit are omitted.
+func protocolsNearLimit(res bird.Parsed, fraction float64) bird.Parsed {
+	protocols, ok := parsedMap(res["protocols"])
+	if !ok {
+		return res
+	}
+
+	selected := bird.Parsed{}
+	for name, p := range protocols {
+		protocol, ok := parsedMap(p)
+		if !ok {
+			continue
+		}
+		limit, ok := parsedNumber(protocol["import_limit"])
+		if !ok || limit <= 0 {
+			continue
+		}
+		routes, _ := parsedMap(protocol["routes"])
+		imported, _ := parsedNumber(routes["imported"])
+		if imported >= fraction*limit {
+			selected[name] = p
+		}
+	}
+
+	// The result is shared with the cache
+	filtered := bird.Parsed{}
+	for key, value := range res {
+		filtered[key] = value
+	}
+	filtered["protocols"] = selected
+	return filtered
+}
+
+func Bgp(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	fraction, err := queryNearLimit(r)
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	res, fromCache := bird.ProtocolsBgp(opts)
+	if fraction == 0 || bird.IsSpecial(res) {
+		return res, fromCache
+	}
+	return protocolsNearLimit(res, fraction), fromCache
+}
+
+// Select the BGP protocols with the neighbor AS
+func protocolsByNeighborAS(res bird.Parsed, as uint64) bird.Parsed {
+	protocols, ok := parsedMap(res["protocols"])
+	if !ok {
+		return res
+	}
+
+	selected := bird.Parsed{}
+	for name, p := range protocols {
+		protocol, ok := parsedMap(p)
+		if !ok {
+			continue
+		}
+		neighborAS, ok := parsedNumber(protocol["neighbor_as"])
+		if ok && neighborAS == float64(as) {
+			selected[name] = p
+		}
+	}
+
+	// The result is shared with the cache
+	filtered := bird.Parsed{}
+	for key, value := range res {
+		filtered[key] = value
+	}
+	filtered["protocols"] = selected
+	return filtered
+}
+
+// BgpNeighborAS gets the BGP protocols of all
+// sessions with a neighbor AS.
+func BgpNeighborAS(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	param, err := validateASParam(ps.ByName("as"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+	as, _ := strconv.ParseUint(param, 10, 32)
+
+	res, fromCache := bird.ProtocolsBgp(opts)
+	if bird.IsSpecial(res) {
+		return res, fromCache
+	}
+	return protocolsByNeighborAS(res, as), fromCache
+}
+
+func ProtocolsShort(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	return bird.ProtocolsShort(opts)
+}
+
+func ProtocolsStates(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	return bird.ProtocolsStates(opts)
+}
+
+func BgpSummary(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	return bird.ProtocolsBgpSummary(opts)
+}
+
+func OspfLsadb(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	return bird.OspfLsadb(opts)
+}
+
+func Rip(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	return bird.Rip(opts)
 }
@@ -0,0 +1,85 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestQueryNearLimit(t *testing.T) {
+	r := httptest.NewRequest("GET", "/protocols/bgp?near_limit=0.9", nil)
+	if fraction, err := queryNearLimit(r); err != nil || fraction != 0.9 {
+		t.Error("Expected fraction 0.9, got:", fraction, err)
+	}
+
+	r = httptest.NewRequest("GET", "/protocols/bgp", nil)
+	if fraction, err := queryNearLimit(r); err != nil || fraction != 0 {
+		t.Error("Expected no fraction, got:", fraction, err)
+	}
+
+	for _, query := range []string{"near_limit=many", "near_limit=-1", "near_limit=0.5&near_limit=0.9"} {
+		r := httptest.NewRequest("GET", "/protocols/bgp?"+query, nil)
+		if _, err := queryNearLimit(r); err == nil {
+			t.Error("Expected an error for query:", query)
+		}
+	}
+}
+
+func TestProtocolsNearLimit(t *testing.T) {
+	res := bird.Parsed{
+		"protocols": bird.Parsed{
+			"near": bird.Parsed{
+				"import_limit": int64(1000),
+				"routes":       bird.Parsed{"imported": int64(950)},
+			},
+			"far": bird.Parsed{
+				"import_limit": int64(1000),
+				"routes":       bird.Parsed{"imported": int64(10)},
+			},
+			"unlimited": bird.Parsed{
+				"routes": bird.Parsed{"imported": int64(100000)},
+			},
+			// Decoded from the redis cache
+			"cached": map[string]interface{}{
+				"import_limit": float64(100),
+				"routes":       map[string]interface{}{"imported": float64(100)},
+			},
+		},
+	}
+
+	protocols := protocolsNearLimit(res, 0.9)["protocols"].(bird.Parsed)
+	if len(protocols) != 2 {
+		t.Fatal("Expected 2 protocols, got:", protocols)
+	}
+	if _, ok := protocols["near"]; !ok {
+		t.Error("Expected protocol near its limit")
+	}
+	if _, ok := protocols["cached"]; !ok {
+		t.Error("Expected cached protocol at its limit")
+	}
+	if len(res["protocols"].(bird.Parsed)) != 4 {
+		t.Error("Expected the original result not to be modified")
+	}
+}
+
+func TestProtocolsByNeighborAS(t *testing.T) {
+	res := bird.Parsed{
+		"protocols": bird.Parsed{
+			"R1_fra": bird.Parsed{"neighbor_as": int64(65001)},
+			"R1_ber": bird.Parsed{"neighbor_as": int64(65001)},
+			"R2":     bird.Parsed{"neighbor_as": int64(65002)},
+			// Decoded from the redis cache
+			"R1_ham": map[string]interface{}{"neighbor_as": float64(65001)},
+		},
+	}
+
+	selected := protocolsByNeighborAS(res, 65001)
+	protocols := selected["protocols"].(bird.Parsed)
+	if len(protocols) != 3 || protocols["R2"] != nil {
+		t.Error("Expected the sessions with AS65001, got:", protocols)
+	}
+	if len(res["protocols"].(bird.Parsed)) != 4 {
+		t.Error("Expected the cached result to be unchanged")
+	}
+}
@@ -0,0 +1,64 @@
+package endpoints
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+// Get a comma separated list from a query parameter
+func queryList(r *http.Request, key string) []string {
+	values := []string{}
+	for _, param := range r.URL.Query()[key] {
+		for _, value := range strings.Split(param, ",") {
+			value = strings.TrimSpace(value)
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// Results from the redis cache are decoded from JSON,
+// so nested values are plain maps and slices instead
+// of bird.Parsed.
+func parsedMap(value interface{}) (bird.Parsed, bool) {
+	switch v := value.(type) {
+	case bird.Parsed:
+		return v, true
+	case map[string]interface{}:
+		return bird.Parsed(v), true
+	}
+	return nil, false
+}
+
+func parsedList(value interface{}) ([]bird.Parsed, bool) {
+	switch v := value.(type) {
+	case []bird.Parsed:
+		return v, true
+	case []interface{}:
+		list := make([]bird.Parsed, 0, len(v))
+		for _, item := range v {
+			p, ok := parsedMap(item)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, p)
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// Numbers from the redis cache are decoded as float
+func parsedNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,136 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+var RawConf RawConfig
+
+// The number of running raw commands
+var rawRunning = struct {
+	sync.Mutex
+	count int
+}{}
+
+// Get the limits of birdc for raw commands from the config
+func rawLimits() bird.RunLimits {
+	return bird.RunLimits{
+		Timeout:        time.Duration(RawConf.Timeout) * time.Second,
+		MaxOutputBytes: RawConf.MaxOutputBytes,
+	}
+}
+
+// Acquire a slot for a raw command, returns false if
+// the maximum of concurrent commands is reached.
+func acquireRaw() bool {
+	rawRunning.Lock()
+	defer rawRunning.Unlock()
+	if RawConf.MaxConcurrent > 0 && rawRunning.count >= RawConf.MaxConcurrent {
+		return false
+	}
+	rawRunning.count++
+	return true
+}
+
+func releaseRaw() {
+	rawRunning.Lock()
+	defer rawRunning.Unlock()
+	rawRunning.count--
+}
+
+// Placeholders available in the templates of the
+// raw command allow-list, e.g. "show route for {net}"
+var rawParamValidators = map[string]func(string) (string, error){
+	"protocol": validateAllowedProtocol,
+	"table":    validateAllowedTable,
+	"net":      validateNetParam,
+	"number":   ValidateNumberParam,
+}
+
+// Match a single token of a command against a token
+// of a template. The template token may contain a
+// placeholder, which is validated.
+func matchRawToken(template string, token string) bool {
+	start := strings.Index(template, "{")
+	end := strings.Index(template, "}")
+	if start < 0 || end < start {
+		return template == token
+	}
+
+	prefix := template[:start]
+	suffix := template[end+1:]
+	if len(token) < len(prefix)+len(suffix) ||
+		!strings.HasPrefix(token, prefix) ||
+		!strings.HasSuffix(token, suffix) {
+		return false
+	}
+
+	validate, ok := rawParamValidators[template[start+1:end]]
+	if !ok {
+		return false
+	}
+	value := token[len(prefix) : len(token)-len(suffix)]
+	if len(value) == 0 {
+		return false
+	}
+	_, err := validate(value)
+	return err == nil
+}
+
+// Check if the command is matched by the template
+func matchRawCommand(template string, cmd []string) bool {
+	tokens := strings.Fields(template)
+	if len(tokens) != len(cmd) {
+		return false
+	}
+	for i, token := range tokens {
+		if !matchRawToken(token, cmd[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check if the command is in the allow-list
+func isRawCommandAllowed(cmd []string) bool {
+	for _, template := range RawConf.Commands {
+		if matchRawCommand(template, cmd) {
+			return true
+		}
+	}
+	return false
+}
+
+func Raw(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	qs := r.URL.Query()
+	if len(qs["cmd"]) != 1 {
+		return ErrorResult(http.StatusBadRequest,
+			fmt.Errorf("need a cmd as single query parameter"))
+	}
+
+	cmd := strings.Fields(qs["cmd"][0])
+	if len(cmd) < 2 || cmd[0] != "show" {
+		return ErrorResult(http.StatusBadRequest,
+			fmt.Errorf("only show commands are supported"))
+	}
+
+	if !isRawCommandAllowed(cmd) {
+		return ErrorResult(http.StatusForbidden,
+			fmt.Errorf("command is not allowed: %s", strings.Join(cmd, " ")))
+	}
+
+	if !acquireRaw() {
+		return ErrorResult(http.StatusTooManyRequests,
+			fmt.Errorf("too many concurrent raw commands"))
+	}
+	defer releaseRaw()
+
+	return bird.RawCommand(opts, strings.Join(cmd[1:], " "), rawLimits())
+}
@@ -0,0 +1,90 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestRawCommandAllowList(t *testing.T) {
+	RawConf = RawConfig{
+		Commands: []string{
+			"show memory",
+			"show route for {net} table '{table}'",
+			"show protocols all {protocol}",
+		},
+	}
+
+	allowed := []string{
+		"show memory",
+		"show  memory ",
+		"show route for 10.0.0.0/8 table 'master4'",
+		"show route for 2001:db8::1 table 'T65001_nada'",
+		"show protocols all R194_42",
+	}
+	for _, cmd := range allowed {
+		if !isRawCommandAllowed(strings.Fields(cmd)) {
+			t.Error(cmd, "should be allowed")
+		}
+	}
+
+	denied := []string{
+		"show memory all",
+		"show route",
+		"show route for foo table 'master4'",
+		"show route for 10.0.0.0/8 table master4",
+		"show route for 10.0.0.0/8 table ''",
+		"show protocols all R194_42;",
+		"show protocols all",
+	}
+	for _, cmd := range denied {
+		if isRawCommandAllowed(strings.Fields(cmd)) {
+			t.Error(cmd, "should not be allowed")
+		}
+	}
+}
+
+func TestRawMaxConcurrent(t *testing.T) {
+	RawConf = RawConfig{MaxConcurrent: 2}
+	defer func() { RawConf = RawConfig{} }()
+
+	if !acquireRaw() || !acquireRaw() {
+		t.Fatal("Expected two raw commands to be admitted")
+	}
+	if acquireRaw() {
+		t.Error("Expected the third raw command to be rejected")
+	}
+
+	releaseRaw()
+	if !acquireRaw() {
+		t.Error("Expected a raw command to be admitted after release")
+	}
+	releaseRaw()
+	releaseRaw()
+}
+
+func TestRawCommandCache(t *testing.T) {
+	defer helperBirdc("protocols_bgp_pipe.sample")()
+	bird.ClientConf.CacheTtl = 5
+	RawConf = RawConfig{Commands: []string{"show protocols all"}}
+	defer func() { RawConf = RawConfig{} }()
+
+	// The raw result must not be served for the protocols
+	w := httptest.NewRecorder()
+	Endpoint(Raw)(w, httptest.NewRequest("GET", "/raw?cmd=show+protocols+all", nil), nil)
+	if w.Code != http.StatusOK {
+		t.Fatal("Expected status 200 for the raw command, got:", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	Endpoint(Bgp)(w, httptest.NewRequest("GET", "/protocols/bgp", nil), nil)
+	if w.Code != http.StatusOK {
+		t.Fatal("Expected status 200 for the BGP protocols, got:", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"R194_42"`) {
+		t.Error("Expected the BGP protocols, got:", w.Body.String())
+	}
+}
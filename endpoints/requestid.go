@@ -0,0 +1,51 @@
+package endpoints
+
+// Correlation of requests by an ID
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// RequestIDHeader is the header holding the ID of a request.
+// It is echoed in the response and written to the query log.
+const RequestIDHeader = "X-Request-ID"
+
+// Maximum length of an ID given by a client
+const maxRequestIDLength = 128
+
+// Check if the ID given by a client can be used. Only
+// printable characters without whitespace and quotes
+// are accepted, as the ID is written to the log.
+func isValidRequestID(id string) bool {
+	if len(id) == 0 || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' || c == '"' || c == '\\' {
+			return false
+		}
+	}
+	return true
+}
+
+// Generate a random (version 4) UUID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Get the ID of the request from the header,
+// or generate one if it is missing or invalid.
+func requestID(r *http.Request) string {
+	if id := r.Header.Get(RequestIDHeader); isValidRequestID(id) {
+		return id
+	}
+	return newRequestID()
+}
@@ -0,0 +1,194 @@
+package endpoints
+
+// Reverse DNS of the next hops of routes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+var ResolveConf ResolveConfig
+
+// Lookup of the PTR records of an address,
+// replaced in tests.
+var lookupAddr = net.DefaultResolver.LookupAddr
+
+// Get the timeout of the lookups of a request
+// from the config, defaults to 500ms.
+func resolveTimeout() time.Duration {
+	if ResolveConf.Timeout > 0 {
+		return time.Duration(ResolveConf.Timeout) * time.Millisecond
+	}
+	return 500 * time.Millisecond
+}
+
+// Get the time resolved hostnames are cached
+// from the config, defaults to one hour.
+func resolveCacheTtl() time.Duration {
+	if ResolveConf.CacheTtl > 0 {
+		return time.Duration(ResolveConf.CacheTtl) * time.Minute
+	}
+	return time.Hour
+}
+
+// Get the maximum number of uncached lookups
+// per request from the config, defaults to 32.
+func resolveMaxLookups() int {
+	if ResolveConf.MaxLookups > 0 {
+		return ResolveConf.MaxLookups
+	}
+	return 32
+}
+
+// Check if the next hops are resolved, as requested
+// with the resolve query parameter.
+func queryResolve(r *http.Request) (bool, error) {
+	values := r.URL.Query()["resolve"]
+	switch {
+	case len(values) == 0:
+		return false, nil
+	case len(values) > 1:
+		return false, fmt.Errorf("need resolve as single query parameter")
+	}
+
+	switch values[0] {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("resolve must be either 'true' or 'false'")
+}
+
+// A cached lookup. Failed lookups are cached
+// as well with an empty hostname.
+type resolvedHostname struct {
+	hostname string
+	expires  time.Time
+}
+
+var hostnameCache = struct {
+	sync.Mutex
+	entries map[string]resolvedHostname
+}{
+	entries: map[string]resolvedHostname{},
+}
+
+func cachedHostname(addr string, now time.Time) (string, bool) {
+	hostnameCache.Lock()
+	defer hostnameCache.Unlock()
+
+	entry, ok := hostnameCache.entries[addr]
+	if !ok {
+		return "", false
+	}
+	if now.After(entry.expires) {
+		delete(hostnameCache.entries, addr)
+		return "", false
+	}
+	return entry.hostname, true
+}
+
+func cacheHostname(addr string, hostname string, now time.Time) {
+	hostnameCache.Lock()
+	defer hostnameCache.Unlock()
+	hostnameCache.entries[addr] = resolvedHostname{
+		hostname: hostname,
+		expires:  now.Add(resolveCacheTtl()),
+	}
+}
+
+// Resolve the hostnames of the addresses. The uncached
+// lookups are limited and run concurrently within the
+// timeout, addresses not resolved in time are missing.
+func resolveHostnames(addrs []string) map[string]string {
+	now := time.Now()
+	hostnames := map[string]string{}
+	lookups := []string{}
+	for _, addr := range addrs {
+		if hostname, ok := cachedHostname(addr, now); ok {
+			hostnames[addr] = hostname
+		} else if len(lookups) < resolveMaxLookups() {
+			lookups = append(lookups, addr)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout())
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, addr := range lookups {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			names, err := lookupAddr(ctx, addr)
+			if ctx.Err() != nil {
+				// Timeouts are not cached, the lookup
+				// may succeed with the next request.
+				return
+			}
+			hostname := ""
+			if err == nil && len(names) > 0 {
+				hostname = strings.TrimSuffix(names[0], ".")
+			}
+			cacheHostname(addr, hostname, now)
+
+			mu.Lock()
+			hostnames[addr] = hostname
+			mu.Unlock()
+		}(addr)
+	}
+	wg.Wait()
+
+	return hostnames
+}
+
+// Annotate the routes in the result with the hostname of
+// their next hop as next_hop_hostname, if requested. The
+// hostname is null if the next hop could not be resolved.
+func resolveRoutes(r *http.Request, res bird.Parsed) {
+	if resolve, _ := queryResolve(r); !resolve {
+		return
+	}
+
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return
+	}
+
+	addrs := []string{}
+	seen := map[string]bool{}
+	for _, route := range routes {
+		gateway, _ := route["gateway"].(string)
+		if gateway == "" || seen[gateway] || net.ParseIP(gateway) == nil {
+			continue
+		}
+		seen[gateway] = true
+		addrs = append(addrs, gateway)
+	}
+	hostnames := resolveHostnames(addrs)
+
+	resolved := make([]bird.Parsed, 0, len(routes))
+	for _, route := range routes {
+		// Routes are shared with the cache
+		copied := bird.Parsed{}
+		for k, v := range route {
+			copied[k] = v
+		}
+		copied["next_hop_hostname"] = nil
+		gateway, _ := route["gateway"].(string)
+		if hostname := hostnames[gateway]; hostname != "" {
+			copied["next_hop_hostname"] = hostname
+		}
+		resolved = append(resolved, copied)
+	}
+	res["routes"] = resolved
+}
@@ -0,0 +1,68 @@
+package endpoints
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestResolveRoutes(t *testing.T) {
+	saved := lookupAddr
+	lookups := 0
+	lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+		lookups++
+		if addr == "192.0.2.1" {
+			return []string{"edge-1.example.net."}, nil
+		}
+		return nil, errors.New("no PTR record")
+	}
+	defer func() { lookupAddr = saved }()
+
+	routes := []bird.Parsed{
+		{"network": "10.0.0.0/8", "gateway": "192.0.2.1"},
+		{"network": "10.1.0.0/16", "gateway": "192.0.2.1"},
+		{"network": "10.2.0.0/16", "gateway": "192.0.2.2"},
+	}
+	res := bird.Parsed{"routes": routes}
+
+	r := httptest.NewRequest("GET", "/routes/table/master?resolve=true", nil)
+	resolveRoutes(r, res)
+
+	resolved := res["routes"].([]bird.Parsed)
+	if resolved[0]["next_hop_hostname"] != "edge-1.example.net" {
+		t.Error("Unexpected hostname:", resolved[0]["next_hop_hostname"])
+	}
+	if resolved[2]["next_hop_hostname"] != nil {
+		t.Error("Expected null hostname, got:", resolved[2]["next_hop_hostname"])
+	}
+	if _, ok := routes[0]["next_hop_hostname"]; ok {
+		t.Error("Cached routes must not be modified")
+	}
+	if lookups != 2 {
+		t.Error("Expected 2 lookups, got:", lookups)
+	}
+
+	// Lookups are cached, including failed ones
+	resolveRoutes(r, bird.Parsed{"routes": routes})
+	if lookups != 2 {
+		t.Error("Expected cached lookups, got:", lookups)
+	}
+}
+
+func TestResolveRoutesNotRequested(t *testing.T) {
+	res := bird.Parsed{"routes": []bird.Parsed{{"gateway": "192.0.2.1"}}}
+	resolveRoutes(httptest.NewRequest("GET", "/routes/table/master", nil), res)
+	if _, ok := res["routes"].([]bird.Parsed)[0]["next_hop_hostname"]; ok {
+		t.Error("Expected no hostname without resolve=true")
+	}
+}
+
+func TestQueryResolve(t *testing.T) {
+	r := httptest.NewRequest("GET", "/routes/table/master?resolve=yes", nil)
+	if _, err := queryResolve(r); err == nil {
+		t.Error("Expected an error for an invalid resolve parameter")
+	}
+}
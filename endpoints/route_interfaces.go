@@ -0,0 +1,70 @@
+package endpoints
+
+// Distribution of the routes of a table across the
+// interfaces of their next hops
+
+import (
+	"net/http"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+// Get the distinct interfaces of the next hops of a route.
+// Routes without next hops use the interface of the route.
+func routeInterfaces(route bird.Parsed) []string {
+	ifaces := []string{}
+	seen := map[string]bool{}
+	add := func(iface interface{}) {
+		name, _ := iface.(string)
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		ifaces = append(ifaces, name)
+	}
+
+	if nextHops, ok := parsedList(route["next_hops"]); ok && len(nextHops) > 0 {
+		for _, nextHop := range nextHops {
+			add(nextHop["interface"])
+		}
+	} else {
+		add(route["interface"])
+	}
+	return ifaces
+}
+
+// Count the routes per interface. A multipath route is
+// counted once for each interface of its next hops.
+func countRouteInterfaces(routes []bird.Parsed) bird.Parsed {
+	counts := bird.Parsed{}
+	for _, route := range routes {
+		for _, iface := range routeInterfaces(route) {
+			n, _ := counts[iface].(int)
+			counts[iface] = n + 1
+		}
+	}
+	return counts
+}
+
+func TableRoutesInterfaces(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	res, fromCache := bird.RoutesTable(opts, table)
+	if bird.IsSpecial(res) {
+		return res, fromCache
+	}
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return res, fromCache
+	}
+
+	return bird.Parsed{
+		"interfaces": countRouteInterfaces(routes),
+		"ttl":        res["ttl"],
+		"cached_at":  res["cached_at"],
+	}, fromCache
+}
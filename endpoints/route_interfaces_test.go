@@ -0,0 +1,28 @@
+package endpoints
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestCountRouteInterfaces(t *testing.T) {
+	routes := []bird.Parsed{
+		{"interface": "eth0"},
+		{"interface": "eth0", "next_hops": []bird.Parsed{
+			{"gateway": "10.0.0.1", "interface": "eth0"},
+		}},
+		{"interface": "eth1", "next_hops": []interface{}{ // Multipath, from redis
+			map[string]interface{}{"gateway": "10.1.0.1", "interface": "eth1"},
+			map[string]interface{}{"gateway": "10.1.0.2", "interface": "eth1"},
+			map[string]interface{}{"gateway": "10.2.0.1", "interface": "eth2"},
+		}},
+		{"network": "10.3.0.0/16"}, // Unreachable
+	}
+
+	expected := bird.Parsed{"eth0": 2, "eth1": 1, "eth2": 1}
+	if counts := countRouteInterfaces(routes); !reflect.DeepEqual(counts, expected) {
+		t.Error("Unexpected interface counts:", counts)
+	}
+}
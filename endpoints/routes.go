@@ -3,48 +3,79 @@ package endpoints
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/alice-lg/birdwatcher/bird"
 	"github.com/julienschmidt/httprouter"
 )
 
-func ProtoRoutes(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
+func ProtoRoutes(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	protocol, err := ValidateProtocolParam(ps.ByName("protocol"))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesProto(useCache, protocol)
+	return bird.RoutesProto(opts, protocol)
 }
 
-func RoutesFiltered(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
+// ProtoRoutesAll gets the routes of a protocol with all
+// attributes, which ProtoRoutes already does. It is kept as
+// its own module, so it can be enabled and cached separately.
+func ProtoRoutesAll(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	return ProtoRoutes(r, ps, opts)
+}
+
+func StaticRoutes(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	protocol, err := ValidateProtocolParam(ps.ByName("protocol"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	return bird.RoutesStatic(opts, protocol)
+}
+
+func RoutesFiltered(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	protocol, err := ValidateProtocolParam(ps.ByName("protocol"))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesFiltered(useCache, protocol)
+	return bird.RoutesFiltered(opts, protocol)
 }
 
-func RoutesExport(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
+func RoutesExport(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	protocol, err := ValidateProtocolParam(ps.ByName("protocol"))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesExport(useCache, protocol)
+	return bird.RoutesExport(opts, protocol)
 }
 
-func RoutesNoExport(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
+func TableExportRoutes(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	protocol, err := ValidateProtocolParam(ps.ByName("protocol"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	return bird.RoutesTableExport(opts, table, protocol)
+}
+
+func RoutesNoExport(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	protocol, err := ValidateProtocolParam(ps.ByName("protocol"))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesNoExport(useCache, protocol)
+	return bird.RoutesNoExport(opts, protocol)
 }
 
-func RoutesPrefixed(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
+func RoutesPrefixed(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	qs := r.URL.Query()
 	prefixl := qs["prefix"]
 	if len(prefixl) != 1 {
@@ -56,29 +87,29 @@ func RoutesPrefixed(r *http.Request, ps httprouter.Params, useCache bool) (bird.
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesPrefixed(useCache, prefix)
+	return bird.RoutesPrefixed(opts, prefix)
 }
 
-func TableRoutes(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	table, err := ValidateProtocolParam(ps.ByName("table"))
+func TableRoutes(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesTable(useCache, table)
+	return bird.RoutesTable(opts, table)
 }
 
-func TableRoutesFiltered(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	table, err := ValidateProtocolParam(ps.ByName("table"))
+func TableRoutesFiltered(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesTableFiltered(useCache, table)
+	return bird.RoutesTableFiltered(opts, table)
 }
 
-func TableAndPeerRoutes(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	table, err := ValidateProtocolParam(ps.ByName("table"))
+func TableAndPeerRoutes(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
@@ -88,92 +119,264 @@ func TableAndPeerRoutes(r *http.Request, ps httprouter.Params, useCache bool) (b
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesTableAndPeer(useCache, table, peer)
+	return bird.RoutesTableAndPeer(opts, table, peer)
 }
 
-func ProtoCount(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
+// Validate an AS number, which must fit into 32 bits
+func validateASParam(value string) (string, error) {
+	as, err := ValidateNumberParam(value)
+	if err != nil {
+		return "", err
+	}
+	if _, err := strconv.ParseUint(as, 10, 32); err != nil {
+		return "", fmt.Errorf("invalid AS number: %s", as)
+	}
+	return as, nil
+}
+
+func TableAndOriginRoutes(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	as, err := validateASParam(ps.ByName("as"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	return bird.RoutesTableAndOrigin(opts, table, as)
+}
+
+func ProtoCount(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	protocol, err := ValidateProtocolParam(ps.ByName("protocol"))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesProtoCount(useCache, protocol)
+	return bird.RoutesProtoCount(opts, protocol)
 }
 
-func ProtoPrimaryCount(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
+func ProtoPrimaryCount(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	protocol, err := ValidateProtocolParam(ps.ByName("protocol"))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
-	return bird.RoutesProtoPrimaryCount(useCache, protocol)
+	return bird.RoutesProtoPrimaryCount(opts, protocol)
 }
 
-func TableCount(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	table, err := ValidateProtocolParam(ps.ByName("table"))
+func TableCount(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesTableCount(useCache, table)
+	return bird.RoutesTableCount(opts, table)
 }
 
-func RouteNet(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	net, err := ValidatePrefixParam(ps.ByName("net"))
+// Validate the net param and make sure it is a parsable
+// IPv4 or IPv6 address or prefix.
+func validateNetParam(value string) (string, error) {
+	net, err := ValidatePrefixParam(value)
 	if err != nil {
-		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
+		return "", err
 	}
+	if _, _, err := bird.ParseNet(net); err != nil {
+		return "", err
+	}
+	return net, nil
+}
 
-	return bird.RoutesLookupTable(useCache, net, "master")
+// Validate an address, which must not be a prefix
+func validateAddrParam(value string) (string, error) {
+	addr, err := ValidateLengthAndCharset(value, 80, "1234567890abcdef.:")
+	if err != nil {
+		return "", err
+	}
+	if _, _, err := bird.ParseNet(addr); err != nil {
+		return "", err
+	}
+	return addr, nil
 }
 
-func RouteNetMask(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	net, err := ValidatePrefixParam(ps.ByName("net"))
+func RouteFor(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	addr, err := validateAddrParam(ps.ByName("addr"))
 	if err != nil {
-		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
+		return ErrorResult(http.StatusBadRequest, err)
 	}
 
-	mask, err := ValidateNetMaskParam(ps.ByName("mask"))
+	table := netTable(addr)
+	if !isTableAllowed(table) {
+		return ErrorResult(http.StatusForbidden, fmt.Errorf("table is not allowed: %s", table))
+	}
+
+	return bird.RoutesLookupAddr(opts, addr, table)
+}
+
+func TableAndPeerRoutesCount(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
 	if err != nil {
-		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	peer, err := ValidatePrefixParam(ps.ByName("peer"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
 	}
 
-	return bird.RoutesLookupTable(useCache, net+"/"+mask, "master")
+	return bird.RoutesTableAndPeerCount(opts, table, peer)
 }
 
-func RouteNetTable(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	net, err := ValidatePrefixParam(ps.ByName("net"))
+func TablePrimaryCount(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
 	if err != nil {
-		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
+		return ErrorResult(http.StatusBadRequest, err)
 	}
 
-	table, err := ValidateProtocolParam(ps.ByName("table"))
+	return bird.RoutesTablePrimaryCount(opts, table)
+}
+
+func TableMemory(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
 	if err != nil {
-		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
+		return ErrorResult(http.StatusBadRequest, err)
 	}
 
-	return bird.RoutesLookupTable(useCache, net, table)
+	return bird.RoutesTableMemory(opts, table)
 }
 
-func RouteNetMaskTable(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	net, err := ValidatePrefixParam(ps.ByName("net"))
+func RouteNet(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	net, err := validateNetParam(ps.ByName("net"))
 	if err != nil {
-		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
+		return ErrorResult(http.StatusBadRequest, err)
 	}
 
+	table := netTable(net)
+	if !isTableAllowed(table) {
+		return ErrorResult(http.StatusForbidden, fmt.Errorf("table is not allowed: %s", table))
+	}
+
+	return bird.RoutesLookupTable(opts, net, table)
+}
+
+// RouteNetPeer gets the routes for a net learnt
+// from the peer, given by its address.
+func RouteNetPeer(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	net, err := validateNetParam(ps.ByName("net"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	// The peer is either the address of the
+	// neighbor or the name of the protocol
+	peer := ps.ByName("peer")
+	addr, addrErr := validateAddrParam(peer)
+	if addrErr != nil {
+		if peer == "" {
+			return ErrorResult(http.StatusBadRequest, fmt.Errorf("peer must be an address or a protocol"))
+		}
+		if _, err := ValidateProtocolParam(peer); err != nil {
+			return ErrorResult(http.StatusBadRequest, err)
+		}
+		if !isProtocolAllowed(peer) {
+			return ErrorResult(http.StatusForbidden, fmt.Errorf("protocol is not allowed: %s", peer))
+		}
+	}
+
+	table := netTable(net)
+	if !isTableAllowed(table) {
+		return ErrorResult(http.StatusForbidden, fmt.Errorf("table is not allowed: %s", table))
+	}
+
+	if addrErr != nil {
+		return bird.RoutesLookupTableProtocol(opts, net, table, peer)
+	}
+	return bird.RoutesLookupTablePeer(opts, net, table, addr)
+}
+
+func RouteNetTables(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	net, err := validateNetParam(ps.ByName("net"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	res, fromCache := bird.RoutesLookupTables(opts, net)
+	if bird.IsSpecial(res) {
+		return res, fromCache
+	}
+	return selectAllowedTables(res), fromCache
+}
+
+func RouteNetExports(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	net, err := validateNetParam(ps.ByName("net"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	return bird.RoutesLookupExports(opts, net)
+}
+
+func RouteNetMask(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	mask, err := ValidateNetMaskParam(ps.ByName("mask"))
 	if err != nil {
-		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
+		return ErrorResult(http.StatusBadRequest, err)
 	}
 
-	table, err := ValidateProtocolParam(ps.ByName("table"))
+	net, err := validateNetParam(ps.ByName("net") + "/" + mask)
 	if err != nil {
-		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	table := netTable(net)
+	if !isTableAllowed(table) {
+		return ErrorResult(http.StatusForbidden, fmt.Errorf("table is not allowed: %s", table))
+	}
+
+	return bird.RoutesLookupTable(opts, net, table)
+}
+
+func RouteNetTable(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	net, err := validateNetParam(ps.ByName("net"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	table, err := ValidateProtocolParam(tableParam(r, ps))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	if err := checkNetTableFamily(net, table); err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	return bird.RoutesLookupTable(opts, net, table)
+}
+
+func RouteNetMaskTable(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	mask, err := ValidateNetMaskParam(ps.ByName("mask"))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	net, err := validateNetParam(ps.ByName("net") + "/" + mask)
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	table, err := ValidateProtocolParam(tableParam(r, ps))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	if err := checkNetTableFamily(net, table); err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
 	}
 
-	return bird.RoutesLookupTable(useCache, net+"/"+mask, table)
+	return bird.RoutesLookupTable(opts, net, table)
 }
 
-func PipeRoutesFiltered(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
+func PipeRoutesFiltered(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	qs := r.URL.Query()
 
 	if len(qs["table"]) != 1 {
@@ -192,10 +395,10 @@ func PipeRoutesFiltered(r *http.Request, ps httprouter.Params, useCache bool) (b
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.PipeRoutesFiltered(useCache, pipe, table)
+	return bird.PipeRoutesFiltered(opts, pipe, table)
 }
 
-func PipeRoutesFilteredCount(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
+func PipeRoutesFilteredCount(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	qs := r.URL.Query()
 
 	if len(qs["table"]) != 1 {
@@ -222,14 +425,14 @@ func PipeRoutesFilteredCount(r *http.Request, ps httprouter.Params, useCache boo
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.PipeRoutesFilteredCount(useCache, pipe, table, address)
+	return bird.PipeRoutesFilteredCount(opts, pipe, table, address)
 }
 
-func PeerRoutes(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
+func PeerRoutes(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
 	peer, err := ValidatePrefixParam(ps.ByName("peer"))
 	if err != nil {
 		return bird.Parsed{"error": fmt.Sprintf("%s", err)}, false
 	}
 
-	return bird.RoutesPeer(useCache, peer)
+	return bird.RoutesPeer(opts, peer)
 }
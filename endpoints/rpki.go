@@ -0,0 +1,103 @@
+package endpoints
+
+// Selection of routes by their RPKI validation state
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+var RpkiConf RpkiConfig
+
+// RPKI validation states, as requested with ?rpki
+const (
+	rpkiValid    = "valid"
+	rpkiInvalid  = "invalid"
+	rpkiUnknown  = "unknown"
+	rpkiNotFound = "notfound"
+)
+
+// Get the communities marking the states from the config
+func rpkiStateCommunities() map[string][]string {
+	return map[string][]string{
+		rpkiValid:    RpkiConf.Valid,
+		rpkiInvalid:  RpkiConf.Invalid,
+		rpkiUnknown:  RpkiConf.Unknown,
+		rpkiNotFound: RpkiConf.NotFound,
+	}
+}
+
+// Check if the RPKI state of routes is available,
+// which requires the communities in the config.
+func isRpkiConfigured() bool {
+	for _, communities := range rpkiStateCommunities() {
+		if len(communities) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the requested RPKI state from the query,
+// which is empty if no state is requested.
+func queryRpki(r *http.Request) (string, error) {
+	values := r.URL.Query()["rpki"]
+	switch {
+	case len(values) == 0:
+		return "", nil
+	case len(values) > 1:
+		return "", fmt.Errorf("need rpki as single query parameter")
+	}
+
+	switch values[0] {
+	case rpkiValid, rpkiInvalid, rpkiUnknown, rpkiNotFound:
+		return values[0], nil
+	}
+	return "", fmt.Errorf("rpki must be one of 'valid', 'invalid', 'unknown' or 'notfound'")
+}
+
+// Get the RPKI state of a route by the first configured
+// (large) community it has. Routes without one of the
+// communities are unknown.
+func routeRpkiState(route bird.Parsed) string {
+	bgp, _ := parsedMap(route["bgp"])
+	communities := routeCommunities(bgp, "communities")
+	largeCommunities := routeCommunities(bgp, "large_communities")
+
+	for _, state := range []string{rpkiValid, rpkiInvalid, rpkiNotFound, rpkiUnknown} {
+		for _, value := range rpkiStateCommunities()[state] {
+			if c, err := parseCommunity(value, 2, 0xffff); err == nil && hasCommunity(communities, c) {
+				return state
+			}
+			if c, err := parseCommunity(value, 3, 0xffffffff); err == nil && hasCommunity(largeCommunities, c) {
+				return state
+			}
+		}
+	}
+	return rpkiUnknown
+}
+
+// Reduce the routes in the result to the routes with the
+// RPKI state requested. Without RPKI communities in the
+// config, the routes are not filtered.
+func selectRouteRpki(r *http.Request, res bird.Parsed) {
+	state, err := queryRpki(r)
+	if err != nil || state == "" || !isRpkiConfigured() {
+		return
+	}
+
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return
+	}
+
+	selected := make([]bird.Parsed, 0, len(routes))
+	for _, route := range routes {
+		if routeRpkiState(route) == state {
+			selected = append(selected, route)
+		}
+	}
+	res["routes"] = selected
+}
@@ -0,0 +1,70 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestQueryRpkiInvalid(t *testing.T) {
+	for _, query := range []string{"?rpki=bogus", "?rpki=valid&rpki=invalid"} {
+		r := httptest.NewRequest("GET", "/routes/table/master"+query, nil)
+		if _, err := queryRpki(r); err == nil {
+			t.Error("Expected error for", query)
+		}
+	}
+}
+
+func TestSelectRouteRpki(t *testing.T) {
+	routes := func() bird.Parsed {
+		return bird.Parsed{"routes": []bird.Parsed{
+			{"network": "10.0.0.0/24", "bgp": bird.Parsed{
+				"large_communities": [][]int64{{65000, 1000, 1}},
+			}},
+			{"network": "10.0.1.0/24", "bgp": bird.Parsed{
+				"communities": [][]int64{{65000, 2}},
+			}},
+			{"network": "10.0.2.0/24", "bgp": bird.Parsed{}},
+		}}
+	}
+
+	// Without communities the routes are not filtered
+	res := routes()
+	r := httptest.NewRequest("GET", "/routes/table/master?rpki=invalid", nil)
+	selectRouteRpki(r, res)
+	if len(res["routes"].([]bird.Parsed)) != 3 {
+		t.Error("Expected routes not to be filtered:", res["routes"])
+	}
+
+	RpkiConf = RpkiConfig{
+		Valid:   []string{"65000:1000:1"},
+		Invalid: []string{"65000:1000:2", "65000:2"},
+	}
+	defer func() { RpkiConf = RpkiConfig{} }()
+
+	tests := []struct {
+		query   string
+		network string
+	}{
+		{"?rpki=valid", "10.0.0.0/24"},
+		{"?rpki=invalid", "10.0.1.0/24"},
+		{"?rpki=unknown", "10.0.2.0/24"},
+	}
+	for _, test := range tests {
+		res := routes()
+		r := httptest.NewRequest("GET", "/routes/table/master"+test.query, nil)
+		selectRouteRpki(r, res)
+		selected := res["routes"].([]bird.Parsed)
+		if len(selected) != 1 || selected[0]["network"] != test.network {
+			t.Error("Unexpected routes for", test.query, ":", selected)
+		}
+	}
+
+	res = routes()
+	r = httptest.NewRequest("GET", "/routes/table/master?rpki=notfound", nil)
+	selectRouteRpki(r, res)
+	if len(res["routes"].([]bird.Parsed)) != 0 {
+		t.Error("Expected no routes not found:", res["routes"])
+	}
+}
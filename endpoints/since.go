@@ -0,0 +1,66 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+// Get the point in time from the t query parameter
+func querySince(r *http.Request) (time.Time, error) {
+	qs := r.URL.Query()
+	if len(qs["t"]) != 1 {
+		return time.Time{}, fmt.Errorf("need a timestamp as single query parameter t")
+	}
+	t, err := time.Parse(time.RFC3339, qs["t"][0])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("t must be a RFC3339 timestamp")
+	}
+	return t, nil
+}
+
+// Select the routes known after the given time. Routes
+// with an unknown age are kept, as they might be newer.
+func routesSince(res bird.Parsed, t time.Time) bird.Parsed {
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return res
+	}
+
+	selected := []bird.Parsed{}
+	for _, route := range routes {
+		since := routeSince(route)
+		if since.IsZero() || since.After(t) {
+			selected = append(selected, route)
+		}
+	}
+
+	// The result is shared with the cache
+	filtered := bird.Parsed{}
+	for key, value := range res {
+		filtered[key] = value
+	}
+	filtered["routes"] = selected
+	return filtered
+}
+
+func TableRoutesSince(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	t, err := querySince(r)
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	res, fromCache := bird.RoutesTable(opts, table)
+	if bird.IsSpecial(res) {
+		return res, fromCache
+	}
+	return routesSince(res, t), fromCache
+}
@@ -0,0 +1,50 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestQuerySince(t *testing.T) {
+	r := httptest.NewRequest("GET", "/routes/table/master/since?t=2021-03-01T12:00:00Z", nil)
+	since, err := querySince(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !since.Equal(time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Error("Unexpected time:", since)
+	}
+
+	for _, query := range []string{"", "?t=yesterday", "?t=2021-03-01", "?t=2021-03-01T12:00:00Z&t=2021-03-02T12:00:00Z"} {
+		r := httptest.NewRequest("GET", "/routes/table/master/since"+query, nil)
+		if _, err := querySince(r); err == nil {
+			t.Error("Expected an error for query:", query)
+		}
+	}
+}
+
+func TestRoutesSince(t *testing.T) {
+	res := bird.Parsed{
+		"routes": []bird.Parsed{
+			{"network": "10.0.0.0/8", "age": "2021-02-28 10:00:00"},
+			{"network": "10.1.0.0/16", "age": "2021-03-01 13:00:00"},
+			{"network": "10.2.0.0/16", "age": "unknown"},
+		},
+	}
+	since := time.Date(2021, 3, 1, 12, 0, 0, 0, time.Local)
+
+	filtered := routesSince(res, since)
+	routes := filtered["routes"].([]bird.Parsed)
+	if len(routes) != 2 {
+		t.Fatal("Expected 2 routes, got:", routes)
+	}
+	if routes[0]["network"] != "10.1.0.0/16" || routes[1]["network"] != "10.2.0.0/16" {
+		t.Error("Unexpected routes:", routes)
+	}
+	if len(res["routes"].([]bird.Parsed)) != 3 {
+		t.Error("Expected the original result not to be modified")
+	}
+}
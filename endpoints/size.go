@@ -0,0 +1,70 @@
+package endpoints
+
+// Accounting of the response sizes
+
+import (
+	"io"
+	"net/http"
+)
+
+// ResponseSize of an endpoint: the bytes of the serialized
+// response and the bytes written to the client, which are
+// less if the response was compressed.
+type ResponseSize struct {
+	Serialized int
+	Written    int
+}
+
+// ResponseSizeRecorder is implemented by response writers,
+// which account the sizes of the responses of the endpoints,
+// like the query log.
+type ResponseSizeRecorder interface {
+	RecordResponseSize(size ResponseSize)
+}
+
+// countingWriter counts the bytes written
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
+// countingResponse counts the bytes written to the client
+type countingResponse struct {
+	http.ResponseWriter
+	n int
+}
+
+func (c *countingResponse) Write(p []byte) (int, error) {
+	n, err := c.ResponseWriter.Write(p)
+	c.n += n
+	return n, err
+}
+
+// Flush the streamed response
+func (c *countingResponse) Flush() {
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Record the size of the response with the response
+// writer, if supported. Responses, which are not
+// serialized separately, like errors, are written
+// as serialized.
+func recordResponseSize(w http.ResponseWriter, written int, serialized *countingWriter) {
+	recorder, ok := w.(ResponseSizeRecorder)
+	if !ok {
+		return
+	}
+	size := ResponseSize{Serialized: written, Written: written}
+	if serialized != nil {
+		size.Serialized = serialized.n
+	}
+	recorder.RecordResponseSize(size)
+}
@@ -0,0 +1,51 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+type sizeRecorder struct {
+	*httptest.ResponseRecorder
+	size *ResponseSize
+}
+
+func (r *sizeRecorder) RecordResponseSize(size ResponseSize) {
+	r.size = &size
+}
+
+func TestEndpointResponseSize(t *testing.T) {
+	handle := Endpoint(func(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+		return bird.Parsed{"description": strings.Repeat("a", 10000)}, false
+	})
+
+	for _, gzip := range []bool{false, true} {
+		r := httptest.NewRequest("GET", "/status", nil)
+		if gzip {
+			r.Header.Set("Accept-Encoding", "gzip")
+		}
+		w := &sizeRecorder{ResponseRecorder: httptest.NewRecorder()}
+		handle(w, r, nil)
+
+		if w.size == nil {
+			t.Fatal("Expected the size to be recorded")
+		}
+		if w.size.Written != w.Body.Len() {
+			t.Error("Expected", w.Body.Len(), "bytes written, got:", w.size.Written)
+		}
+		if w.size.Serialized < 10000 {
+			t.Error("Expected the serialized size, got:", w.size.Serialized)
+		}
+		if gzip && w.size.Written >= w.size.Serialized {
+			t.Error("Expected the compressed response to be smaller, got:", w.size)
+		}
+		if !gzip && w.size.Written != w.size.Serialized {
+			t.Error("Expected the uncompressed sizes to match, got:", w.size)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package endpoints
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+// Compare two routes by a sort key. Returns true
+// if the first route is sorted before the second.
+type routeLess func(a, b bird.Parsed) bool
+
+var routeSortKeys = map[string]routeLess{
+	"prefix":      lessByPrefix,
+	"as_path_len": lessByAsPathLen,
+	"age":         lessByAge,
+	"next_hop":    lessByNextHop,
+}
+
+// Get the sort key and order from the query. Routes are
+// only sorted if requested, the default key is the prefix.
+func querySort(r *http.Request) (string, bool, error) {
+	qs := r.URL.Query()
+	if len(qs["sort"]) == 0 && len(qs["order"]) == 0 {
+		return "", false, nil
+	}
+	if len(qs["sort"]) > 1 || len(qs["order"]) > 1 {
+		return "", false, fmt.Errorf("need sort and order as single query parameters")
+	}
+
+	key := "prefix"
+	if len(qs["sort"]) == 1 {
+		key = qs["sort"][0]
+	}
+	if _, ok := routeSortKeys[key]; !ok {
+		return "", false, fmt.Errorf("routes can not be sorted by '%s'", key)
+	}
+
+	desc := false
+	if len(qs["order"]) == 1 {
+		switch qs["order"][0] {
+		case "asc":
+		case "desc":
+			desc = true
+		default:
+			return "", false, fmt.Errorf("order must be either 'asc' or 'desc'")
+		}
+	}
+
+	return key, desc, nil
+}
+
+// Sort the routes in the result as requested with
+// the sort and order query parameters.
+func sortRoutes(r *http.Request, res bird.Parsed) {
+	key, desc, err := querySort(r)
+	if err != nil || key == "" {
+		return
+	}
+
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return
+	}
+
+	less := routeSortKeys[key]
+	sorted := make([]bird.Parsed, len(routes))
+	copy(sorted, routes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if desc {
+			return less(sorted[j], sorted[i])
+		}
+		return less(sorted[i], sorted[j])
+	})
+	res["routes"] = sorted
+}
+
+// Compare IP addresses numerically, IPv4 before IPv6.
+// Unparsable addresses are compared as strings.
+func lessIP(a, b string) bool {
+	ipA := net.ParseIP(a)
+	ipB := net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a < b
+	}
+	if (ipA.To4() == nil) != (ipB.To4() == nil) {
+		return ipA.To4() != nil
+	}
+	return bytes.Compare(ipA.To16(), ipB.To16()) < 0
+}
+
+func lessByPrefix(a, b bird.Parsed) bool {
+	netA, _ := a["network"].(string)
+	netB, _ := b["network"].(string)
+
+	ipA, prefixA, errA := net.ParseCIDR(netA)
+	ipB, prefixB, errB := net.ParseCIDR(netB)
+	if errA != nil || errB != nil {
+		return netA < netB
+	}
+	if !ipA.Equal(ipB) {
+		return lessIP(ipA.String(), ipB.String())
+	}
+	lenA, _ := prefixA.Mask.Size()
+	lenB, _ := prefixB.Mask.Size()
+	return lenA < lenB
+}
+
+func asPathLen(route bird.Parsed) int {
+	bgp, ok := parsedMap(route["bgp"])
+	if !ok {
+		return 0
+	}
+	switch path := bgp["as_path"].(type) {
+	case []string:
+		return len(path)
+	case []interface{}:
+		return len(path)
+	}
+	return 0
+}
+
+func lessByAsPathLen(a, b bird.Parsed) bool {
+	return asPathLen(a) < asPathLen(b)
+}
+
+// Get the time since a route is known. The age
+// is shown by BIRD in the local time zone.
+func routeSince(route bird.Parsed) time.Time {
+	age, _ := route["age"].(string)
+	since, _ := bird.ParseTime(age, time.Now())
+	return since
+}
+
+// Routes with the smallest age, which were learned
+// most recently, are sorted first.
+func lessByAge(a, b bird.Parsed) bool {
+	return routeSince(a).After(routeSince(b))
+}
+
+func lessByNextHop(a, b bird.Parsed) bool {
+	gwA, _ := a["gateway"].(string)
+	gwB, _ := b["gateway"].(string)
+	return lessIP(gwA, gwB)
+}
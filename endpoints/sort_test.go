@@ -0,0 +1,62 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func routeNetworks(res bird.Parsed) []string {
+	networks := []string{}
+	for _, route := range res["routes"].([]bird.Parsed) {
+		networks = append(networks, route["network"].(string))
+	}
+	return networks
+}
+
+func TestSortRoutes(t *testing.T) {
+	routes := []bird.Parsed{
+		{"network": "10.0.0.0/8", "gateway": "192.0.2.10", "age": "2021-03-30 01:58:08",
+			"bgp": bird.Parsed{"as_path": []string{"1", "2", "3"}}},
+		{"network": "2001:db8::/32", "gateway": "2001:db8::1", "age": "2021-03-29 01:58:08",
+			"bgp": bird.Parsed{"as_path": []string{"1"}}},
+		{"network": "9.0.0.0/8", "gateway": "192.0.2.9", "age": "2021-03-31 01:58:08",
+			"bgp": bird.Parsed{"as_path": []string{"1", "2"}}},
+		{"network": "10.0.0.0/16", "gateway": "192.0.2.10", "age": "2021-03-30 01:58:08"},
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"10.0.0.0/8", "2001:db8::/32", "9.0.0.0/8", "10.0.0.0/16"}},
+		{"?sort=prefix", []string{"9.0.0.0/8", "10.0.0.0/8", "10.0.0.0/16", "2001:db8::/32"}},
+		{"?order=desc", []string{"2001:db8::/32", "10.0.0.0/16", "10.0.0.0/8", "9.0.0.0/8"}},
+		{"?sort=as_path_len", []string{"10.0.0.0/16", "2001:db8::/32", "9.0.0.0/8", "10.0.0.0/8"}},
+		{"?sort=age", []string{"9.0.0.0/8", "10.0.0.0/8", "10.0.0.0/16", "2001:db8::/32"}},
+		{"?sort=next_hop", []string{"9.0.0.0/8", "10.0.0.0/8", "10.0.0.0/16", "2001:db8::/32"}},
+	}
+	for _, test := range tests {
+		res := bird.Parsed{"routes": routes}
+		r := httptest.NewRequest("GET", "/routes/table/master"+test.query, nil)
+		sortRoutes(r, res)
+
+		networks := routeNetworks(res)
+		for i := range networks {
+			if networks[i] != test.expected[i] {
+				t.Error(test.query, "sorted routes:", networks, "expected:", test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestQuerySortInvalid(t *testing.T) {
+	for _, query := range []string{"?sort=foo", "?order=up", "?sort=age&sort=prefix"} {
+		r := httptest.NewRequest("GET", "/routes/table/master"+query, nil)
+		if _, _, err := querySort(r); err == nil {
+			t.Error("Expected an error for", query)
+		}
+	}
+}
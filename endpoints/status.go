@@ -7,6 +7,23 @@ import (
 	"github.com/julienschmidt/httprouter"
 )
 
-func Status(r *http.Request, ps httprouter.Params, useCache bool) (bird.Parsed, bool) {
-	return bird.Status(useCache)
+// The maintenance mode is reported with its message
+func Status(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	res, fromCache := bird.Status(opts)
+	ok, message := maintenanceStatus()
+	if !ok || bird.IsSpecial(res) {
+		return res, fromCache
+	}
+
+	// The result is shared with the cache
+	status := bird.Parsed{}
+	for key, value := range res {
+		status[key] = value
+	}
+	status["maintenance"] = bird.Parsed{"message": message}
+	return status, fromCache
+}
+
+func StatusIdentity(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	return bird.StatusIdentity(opts)
 }
@@ -0,0 +1,119 @@
+package endpoints
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+var NetTablesConf NetTablesConfig
+
+// Parse a net or a bare address, which is
+// treated as a host route.
+func parseNetOrAddr(value string) (*net.IPNet, error) {
+	if ip := net.ParseIP(value); ip != nil {
+		bits := 128
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, n, err := net.ParseCIDR(value)
+	return n, err
+}
+
+// Get the table used if the request does not specify one
+// from the config. Defaults to the master table, which is
+// master4 or master6 with BIRD 2.
+func defaultTable() string {
+	if Conf.DefaultTable != "" {
+		return Conf.DefaultTable
+	}
+	return "master"
+}
+
+// Get the table of a request from the route params or the
+// table query parameter, falling back to the default table.
+func tableParam(r *http.Request, ps httprouter.Params) string {
+	if table := ps.ByName("table"); table != "" {
+		return table
+	}
+	if table := r.URL.Query().Get("table"); table != "" {
+		return table
+	}
+	return defaultTable()
+}
+
+// Get the table for looking up a net without a table. The
+// table of the most specific configured prefix containing
+// the net is used, falling back to the default table.
+func netTable(value string) string {
+	lookup, err := parseNetOrAddr(value)
+	if err != nil {
+		return defaultTable()
+	}
+	lookupLen, _ := lookup.Mask.Size()
+
+	table := defaultTable()
+	matchLen := -1
+	for prefix, prefixTable := range NetTablesConf {
+		_, configured, err := net.ParseCIDR(prefix)
+		if err != nil {
+			log.Println("Invalid prefix in net_tables configuration:", prefix)
+			continue
+		}
+		configuredLen, _ := configured.Mask.Size()
+		if configuredLen > lookupLen || !configured.Contains(lookup.IP) {
+			continue
+		}
+		if len(configured.IP) != len(lookup.IP) {
+			continue // Address family mismatch
+		}
+		if configuredLen > matchLen {
+			table = prefixTable
+			matchLen = configuredLen
+		}
+	}
+	return table
+}
+
+// Get the IP version of a table. A table configured in the
+// net_tables only for prefixes of one family is of this
+// family, otherwise BIRD's default tables are known.
+func tableFamily(table string) (string, bool) {
+	families := map[string]bool{}
+	for prefix, prefixTable := range NetTablesConf {
+		if prefixTable != table {
+			continue
+		}
+		if _, version, err := bird.ParseNet(prefix); err == nil {
+			families[version] = true
+		}
+	}
+	if len(families) == 1 {
+		for version := range families {
+			return version, true
+		}
+	}
+
+	return bird.TableFamily(table)
+}
+
+// Check that a net can be looked up in the table. The lookup
+// of a net in a table of the other family has no result.
+func checkNetTableFamily(value string, table string) error {
+	_, version, err := bird.ParseNet(value)
+	if err != nil {
+		return err
+	}
+	if family, ok := tableFamily(table); ok && family != version {
+		return fmt.Errorf(
+			"net %s is IPv%s, but table %s is IPv%s", value, version, table, family)
+	}
+	return nil
+}
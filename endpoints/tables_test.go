@@ -0,0 +1,122 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestNetTable(t *testing.T) {
+	NetTablesConf = NetTablesConfig{
+		"10.0.0.0/8":    "customers",
+		"10.23.0.0/16":  "region_a",
+		"2001:db8::/32": "customers6",
+		"invalid":       "nothing",
+	}
+	defer func() { NetTablesConf = nil }()
+
+	tests := []struct {
+		net   string
+		table string
+	}{
+		{"10.1.0.0/16", "customers"},
+		{"10.23.42.0/24", "region_a"},
+		{"10.23.42.1", "region_a"},
+		{"10.0.0.0/7", "master"},
+		{"192.168.0.0/24", "master"},
+		{"2001:db8:1::/48", "customers6"},
+		{"2001:db8::1", "customers6"},
+		{"::ffff:10.23.42.1", "region_a"},
+	}
+	for _, test := range tests {
+		if table := netTable(test.net); table != test.table {
+			t.Error("Expected table", test.table, "for", test.net, "got:", table)
+		}
+	}
+}
+
+func TestCheckNetTableFamily(t *testing.T) {
+	NetTablesConf = NetTablesConfig{
+		"10.0.0.0/8":      "customers",
+		"2001:db8::/32":   "customers6",
+		"192.0.2.0/24":    "mixed",
+		"2001:db8:1::/48": "mixed",
+	}
+	defer func() { NetTablesConf = nil }()
+
+	bird.BirdVersion = 2
+	defer func() { bird.BirdVersion = 0 }()
+
+	tests := []struct {
+		net   string
+		table string
+		ok    bool
+	}{
+		{"10.1.0.0/16", "customers", true},
+		{"2001:db8::/48", "customers", false},
+		{"2001:db8::/48", "customers6", true},
+		{"10.1.0.0/16", "customers6", false},
+		{"10.1.0.0/16", "mixed", true},
+		{"2001:db8::/48", "mixed", true},
+		{"10.1.0.0/16", "master4", true},
+		{"10.1.0.0/16", "master6", false},
+		{"2001:db8::1", "master4", false},
+		{"2001:db8::1", "master", true},
+		{"10.1.0.0/16", "unknown", true},
+	}
+	for _, test := range tests {
+		err := checkNetTableFamily(test.net, test.table)
+		if (err == nil) != test.ok {
+			t.Error("Unexpected result for", test.net, "in", test.table, "error:", err)
+		}
+	}
+}
+
+func TestTableParam(t *testing.T) {
+	r := httptest.NewRequest("GET", "/routes/table", nil)
+	if table := tableParam(r, nil); table != "master" {
+		t.Error("Expected master table, got:", table)
+	}
+
+	Conf.DefaultTable = "customers"
+	defer func() { Conf.DefaultTable = "" }()
+	if table := tableParam(r, nil); table != "customers" {
+		t.Error("Expected configured default table, got:", table)
+	}
+
+	r = httptest.NewRequest("GET", "/routes/table?table=tenant1", nil)
+	if table := tableParam(r, nil); table != "tenant1" {
+		t.Error("Expected table from query, got:", table)
+	}
+
+	ps := httprouter.Params{{Key: "table", Value: "tenant2"}}
+	if table := tableParam(r, ps); table != "tenant2" {
+		t.Error("Expected table from params, got:", table)
+	}
+}
+
+func TestTableParamValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint endpoint
+	}{
+		{"TablePrimaryCount", TablePrimaryCount},
+		{"TableMemory", TableMemory},
+		{"TableRoutesSince", TableRoutesSince},
+		{"TableRoutesTree", TableRoutesTree},
+		{"TableAndPeerRoutesCount", TableAndPeerRoutesCount},
+		{"TableRoutesInterfaces", TableRoutesInterfaces},
+		{"TableAndOriginRoutes", TableAndOriginRoutes},
+	}
+	r := httptest.NewRequest("GET", "/routes/table", nil)
+	ps := httprouter.Params{{Key: "table", Value: "master'"}}
+	for _, test := range tests {
+		res, _ := test.endpoint(r, ps, bird.RunOptions{})
+		if res[bird.ErrorStatusKey] != http.StatusBadRequest {
+			t.Error("Expected bad request from", test.name, "got:", res)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package endpoints
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/julienschmidt/httprouter"
+)
+
+// prefixNode is a network in the prefix tree with the
+// more specific networks it covers as children.
+type prefixNode struct {
+	network  *net.IPNet
+	routes   int
+	children []*prefixNode
+}
+
+func (n *prefixNode) length() int {
+	length, _ := n.network.Mask.Size()
+	return length
+}
+
+// Check if the node covers the network of another node
+func (n *prefixNode) covers(other *prefixNode) bool {
+	return len(n.network.IP) == len(other.network.IP) &&
+		n.length() <= other.length() &&
+		n.network.Contains(other.network.IP)
+}
+
+// Order networks by family (IPv4 first), address and
+// prefix length, so covering networks come first.
+func lessPrefixNode(a, b *prefixNode) bool {
+	if len(a.network.IP) != len(b.network.IP) {
+		return len(a.network.IP) < len(b.network.IP)
+	}
+	if c := bytes.Compare(a.network.IP, b.network.IP); c != 0 {
+		return c < 0
+	}
+	return a.length() < b.length()
+}
+
+// Build the tree of the networks of the routes. Each network
+// is a child of the most specific network covering it.
+// Networks without covering network are the roots.
+func buildPrefixTree(routes []bird.Parsed) []*prefixNode {
+	byNetwork := map[string]*prefixNode{}
+	nodes := []*prefixNode{}
+	for _, route := range routes {
+		network, _ := route["network"].(string)
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			continue
+		}
+
+		key := ipNet.String()
+		node, ok := byNetwork[key]
+		if !ok {
+			node = &prefixNode{network: ipNet}
+			byNetwork[key] = node
+			nodes = append(nodes, node)
+		}
+		node.routes++
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return lessPrefixNode(nodes[i], nodes[j])
+	})
+
+	// The stack holds the chain of covering networks
+	// of the current network.
+	roots := []*prefixNode{}
+	stack := []*prefixNode{}
+	for _, node := range nodes {
+		for len(stack) > 0 && !stack[len(stack)-1].covers(node) {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, node)
+		}
+		stack = append(stack, node)
+	}
+
+	return roots
+}
+
+func prefixTreeParsed(nodes []*prefixNode) []bird.Parsed {
+	res := make([]bird.Parsed, 0, len(nodes))
+	for _, node := range nodes {
+		res = append(res, bird.Parsed{
+			"network":  node.network.String(),
+			"routes":   node.routes,
+			"children": prefixTreeParsed(node.children),
+		})
+	}
+	return res
+}
+
+func TableRoutesTree(r *http.Request, ps httprouter.Params, opts bird.RunOptions) (bird.Parsed, bool) {
+	table, err := ValidateProtocolParam(tableParam(r, ps))
+	if err != nil {
+		return ErrorResult(http.StatusBadRequest, err)
+	}
+
+	res, fromCache := bird.RoutesTable(opts, table)
+	if bird.IsSpecial(res) {
+		return res, fromCache
+	}
+	routes, ok := parsedList(res["routes"])
+	if !ok {
+		return res, fromCache
+	}
+
+	return bird.Parsed{
+		"tree":      prefixTreeParsed(buildPrefixTree(routes)),
+		"ttl":       res["ttl"],
+		"cached_at": res["cached_at"],
+	}, fromCache
+}
@@ -0,0 +1,39 @@
+package endpoints
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestBuildPrefixTree(t *testing.T) {
+	routes := []bird.Parsed{
+		{"network": "10.1.2.0/24"},
+		{"network": "2001:db8::/32"},
+		{"network": "10.0.0.0/8"},
+		{"network": "10.1.0.0/16"},
+		{"network": "10.1.0.0/16"}, // Second path
+		{"network": "192.168.0.0/16"},
+		{"network": "10.2.0.0/16"},
+		{"network": "2001:db8:1::/48"},
+		{"network": "invalid"},
+	}
+
+	tree := prefixTreeParsed(buildPrefixTree(routes))
+	expected := []bird.Parsed{
+		{"network": "10.0.0.0/8", "routes": 1, "children": []bird.Parsed{
+			{"network": "10.1.0.0/16", "routes": 2, "children": []bird.Parsed{
+				{"network": "10.1.2.0/24", "routes": 1, "children": []bird.Parsed{}},
+			}},
+			{"network": "10.2.0.0/16", "routes": 1, "children": []bird.Parsed{}},
+		}},
+		{"network": "192.168.0.0/16", "routes": 1, "children": []bird.Parsed{}},
+		{"network": "2001:db8::/32", "routes": 1, "children": []bird.Parsed{
+			{"network": "2001:db8:1::/48", "routes": 1, "children": []bird.Parsed{}},
+		}},
+	}
+	if !reflect.DeepEqual(tree, expected) {
+		t.Error("Unexpected tree:", tree)
+	}
+}
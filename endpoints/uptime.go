@@ -0,0 +1,53 @@
+package endpoints
+
+import (
+	"strings"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+// Get the uptime of a protocol from the time of its last
+// state change. Protocols which are not up have no uptime.
+func protocolUptime(protocol bird.Parsed, now time.Time) (int64, bool) {
+	value, _ := protocol["state_changed_at"].(string)
+	changed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, false
+	}
+	if state, _ := protocol["state"].(string); !strings.EqualFold(state, "up") {
+		return 0, true
+	}
+	return int64(now.Sub(changed).Seconds()), true
+}
+
+// Set the uptime of the protocols in the result, so it is
+// up to date even if the result is cached. The protocols
+// are shared with the cache, so they are replaced by copies.
+func setProtocolsUptime(res bird.Parsed, now time.Time) {
+	protocols, ok := parsedMap(res["protocols"])
+	if !ok {
+		return
+	}
+
+	updated := bird.Parsed{}
+	for name, p := range protocols {
+		protocol, ok := parsedMap(p)
+		if !ok {
+			updated[name] = p
+			continue
+		}
+		uptime, ok := protocolUptime(protocol, now)
+		if !ok {
+			updated[name] = p
+			continue
+		}
+		copied := bird.Parsed{}
+		for key, value := range protocol {
+			copied[key] = value
+		}
+		copied["uptime_seconds"] = uptime
+		updated[name] = copied
+	}
+	res["protocols"] = updated
+}
@@ -0,0 +1,41 @@
+package endpoints
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestSetProtocolsUptime(t *testing.T) {
+	now := time.Date(2018, 6, 1, 15, 38, 40, 0, time.UTC)
+	cached := bird.Parsed{
+		"R1": bird.Parsed{"state": "up", "state_changed_at": "2018-05-31T15:38:40Z"},
+		"R2": bird.Parsed{"state": "down", "state_changed_at": "2018-05-31T15:38:40Z"},
+		"R3": bird.Parsed{"state": "up"},
+	}
+	res := bird.Parsed{"protocols": cached}
+	setProtocolsUptime(res, now)
+
+	protocols := res["protocols"].(bird.Parsed)
+	if uptime := protocols["R1"].(bird.Parsed)["uptime_seconds"]; uptime != int64(86400) {
+		t.Error("Expected uptime of one day, got:", uptime)
+	}
+	if uptime := protocols["R2"].(bird.Parsed)["uptime_seconds"]; uptime != int64(0) {
+		t.Error("Expected no uptime for protocols down, got:", uptime)
+	}
+	if _, ok := protocols["R3"].(bird.Parsed)["uptime_seconds"]; ok {
+		t.Error("Expected no uptime without state change")
+	}
+
+	// The cached protocols are not changed
+	if _, ok := cached["R1"].(bird.Parsed)["uptime_seconds"]; ok {
+		t.Error("Expected the cached protocol not to be changed")
+	}
+
+	// The uptime is computed when the response is built
+	setProtocolsUptime(res, now.Add(time.Minute))
+	if uptime := res["protocols"].(bird.Parsed)["R1"].(bird.Parsed)["uptime_seconds"]; uptime != int64(86460) {
+		t.Error("Expected the uptime to grow, got:", uptime)
+	}
+}
@@ -0,0 +1,223 @@
+package endpoints
+
+// Live protocol state updates over WebSocket
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/gorilla/websocket"
+	"github.com/julienschmidt/httprouter"
+)
+
+var WebSocketConf WebSocketConfig
+
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: checkWsOrigin,
+}
+
+// Browsers send the origin of the page opening the
+// connection. Pages of the same host and the configured
+// origins may connect. Other clients send no origin.
+func checkWsOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range WebSocketConf.AllowOrigins {
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Number of updates buffered for a client before
+// the client is considered too slow and disconnected.
+const wsSendBuffer = 16
+
+// protocolsPoller periodically runs `show protocols` and
+// sends the changed protocols to all subscribers.
+type protocolsPoller struct {
+	sync.Mutex
+	subscribers map[chan bird.Parsed]struct{}
+	state       bird.Parsed
+
+	start sync.Once
+}
+
+var protocolsUpdates = &protocolsPoller{
+	subscribers: map[chan bird.Parsed]struct{}{},
+}
+
+// Get the polling interval from the config
+func protocolsPollInterval() time.Duration {
+	if WebSocketConf.ProtocolsInterval > 0 {
+		return time.Duration(WebSocketConf.ProtocolsInterval) * time.Second
+	}
+	return 10 * time.Second
+}
+
+// Register a subscriber. The current state of the
+// protocols is returned, if it was polled before.
+func (p *protocolsPoller) subscribe() (chan bird.Parsed, bird.Parsed) {
+	p.start.Do(func() {
+		go p.run(protocolsPollInterval())
+	})
+
+	ch := make(chan bird.Parsed, wsSendBuffer)
+
+	p.Lock()
+	defer p.Unlock()
+	p.subscribers[ch] = struct{}{}
+	return ch, p.state
+}
+
+func (p *protocolsPoller) unsubscribe(ch chan bird.Parsed) {
+	p.Lock()
+	defer p.Unlock()
+	if _, ok := p.subscribers[ch]; ok {
+		delete(p.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (p *protocolsPoller) run(interval time.Duration) {
+	for {
+		p.poll()
+		time.Sleep(interval)
+	}
+}
+
+// Poll the protocols and publish the changes. Nothing
+// is polled while there are no subscribers.
+func (p *protocolsPoller) poll() {
+	p.Lock()
+	idle := len(p.subscribers) == 0
+	p.Unlock()
+	if idle {
+		return
+	}
+
+	// The poller is rate limited like requests, the
+	// protocols are polled again after the interval.
+	res, _ := bird.ProtocolsShort(bird.RunOptions{})
+	if bird.IsSpecial(res) {
+		return
+	}
+	current, ok := parsedMap(res["protocols"])
+	if !ok {
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	changed, removed := diffProtocols(p.state, current)
+	p.state = current
+	if len(changed) == 0 && len(removed) == 0 {
+		return
+	}
+
+	update := bird.Parsed{
+		"type":      "update",
+		"protocols": changed,
+		"removed":   removed,
+	}
+	for ch := range p.subscribers {
+		select {
+		case ch <- update:
+		default: // The client does not keep up
+			delete(p.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Get the protocols which are new or changed and
+// the names of the removed protocols.
+func diffProtocols(previous bird.Parsed, current bird.Parsed) (bird.Parsed, []string) {
+	changed := bird.Parsed{}
+	for name, protocol := range current {
+		if !reflect.DeepEqual(previous[name], protocol) {
+			changed[name] = protocol
+		}
+	}
+
+	removed := []string{}
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	return changed, removed
+}
+
+// WsProtocols sends the state of the protocols and
+// pushes all changes to the client.
+func WsProtocols(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if err := CheckAccess(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("WebSocket upgrade failed:", err)
+		return
+	}
+	defer conn.Close()
+
+	updates, state := protocolsUpdates.subscribe()
+	defer protocolsUpdates.unsubscribe(updates)
+
+	if state != nil {
+		snapshot := bird.Parsed{
+			"type":      "snapshot",
+			"protocols": state,
+		}
+		if err := conn.WriteJSON(snapshot); err != nil {
+			return
+		}
+	}
+
+	// Messages from the client are discarded, reading
+	// is required to notice the connection is closed.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(update); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
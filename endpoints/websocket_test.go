@@ -0,0 +1,66 @@
+package endpoints
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestDiffProtocols(t *testing.T) {
+	previous := bird.Parsed{
+		"R1": bird.Parsed{"state": "up", "info": "Established"},
+		"R2": bird.Parsed{"state": "up", "info": "Established"},
+		"R3": bird.Parsed{"state": "down", "info": ""},
+	}
+	current := bird.Parsed{
+		"R1": bird.Parsed{"state": "up", "info": "Established"},
+		"R2": bird.Parsed{"state": "start", "info": "Active"},
+		"R4": bird.Parsed{"state": "up", "info": "Established"},
+	}
+
+	changed, removed := diffProtocols(previous, current)
+	expected := bird.Parsed{
+		"R2": current["R2"],
+		"R4": current["R4"],
+	}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Error("Changed protocols:", changed, "expected:", expected)
+	}
+	if !reflect.DeepEqual(removed, []string{"R3"}) {
+		t.Error("Removed protocols:", removed, "expected: [R3]")
+	}
+
+	// Without a previous state all protocols are new
+	changed, removed = diffProtocols(nil, current)
+	if len(changed) != 3 || len(removed) != 0 {
+		t.Error("Expected all protocols to be new, got:", changed, removed)
+	}
+}
+
+func TestCheckWsOrigin(t *testing.T) {
+	WebSocketConf.AllowOrigins = []string{"https://lg.example.com"}
+	defer func() { WebSocketConf.AllowOrigins = nil }()
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"", true},
+		{"http://birdwatcher.example.com:29184", true},
+		{"https://lg.example.com", true},
+		{"https://LG.example.com", true},
+		{"https://evil.example.com", false},
+		{"http://lg.example.com", false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "http://birdwatcher.example.com:29184/ws/protocols", nil)
+		if test.origin != "" {
+			r.Header.Set("Origin", test.origin)
+		}
+		if allowed := checkWsOrigin(r); allowed != test.allowed {
+			t.Error("Expected origin", test.origin, "allowed:", test.allowed, "got:", allowed)
+		}
+	}
+}
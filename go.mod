@@ -6,8 +6,14 @@ require (
 	github.com/BurntSushi/toml v0.3.1
 	github.com/go-redis/redis v6.15.6+incompatible
 	github.com/go-redis/redis/v8 v8.3.3
+	github.com/golang/protobuf v1.4.2
 	github.com/gorilla/handlers v1.4.2
+	github.com/gorilla/websocket v1.4.2
 	github.com/imdario/mergo v0.3.8
 	github.com/julienschmidt/httprouter v1.3.0
 	github.com/kr/pretty v0.1.0
+	golang.org/x/net v0.0.0-20201006153459-a7d1128ccaa0
+	golang.org/x/sys v0.0.0-20200930185726-fdedc70b468f
+	google.golang.org/grpc v1.33.2
+	google.golang.org/protobuf v1.25.0
 )
@@ -40,3 +40,20 @@ func Housekeeping(config HousekeepingConfig, expireCaches bool) {
 		}
 	}
 }
+
+// Periodically write a snapshot of the MemoryCache to disk,
+// so the cache is warm after a restart.
+func PersistCache(config bird.CacheConfig) {
+	interval := time.Duration(config.PersistInterval) * time.Minute
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	for {
+		time.Sleep(interval)
+
+		if err := bird.SaveCache(); err != nil {
+			log.Println("Could not save cache snapshot:", err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+// Listening sockets of the servers
+
+import (
+	"context"
+	"net"
+	"syscall"
+
+	"github.com/alice-lg/birdwatcher/endpoints"
+)
+
+// Listen on the address with the socket options of the
+// config. SO_REUSEPORT and the backlog are only supported
+// on Linux, listening fails elsewhere if they are set.
+func listen(address string, serverConf endpoints.ServerConfig) (net.Listener, error) {
+	lc := net.ListenConfig{}
+	if serverConf.ReusePort {
+		lc.Control = func(network, address string, c syscall.RawConn) error {
+			var err error
+			if cerr := c.Control(func(fd uintptr) {
+				err = setReusePort(fd)
+			}); cerr != nil {
+				return cerr
+			}
+			return err
+		}
+	}
+
+	ln, err := lc.Listen(context.Background(), "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if serverConf.ListenBacklog <= 0 {
+		return ln, nil
+	}
+
+	// The backlog is changed by listening again
+	// on the socket, which is already bound.
+	raw, err := ln.(*net.TCPListener).SyscallConn()
+	if err == nil {
+		if cerr := raw.Control(func(fd uintptr) {
+			err = setListenBacklog(fd, serverConf.ListenBacklog)
+		}); cerr != nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
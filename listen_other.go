@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import (
+	"fmt"
+)
+
+func setReusePort(fd uintptr) error {
+	return fmt.Errorf("reuse_port is only supported on Linux")
+}
+
+func setListenBacklog(fd uintptr, backlog int) error {
+	return fmt.Errorf("listen_backlog is only supported on Linux")
+}
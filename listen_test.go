@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net"
+	"runtime"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/endpoints"
+)
+
+func TestListenReusePort(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("SO_REUSEPORT is only supported on Linux")
+	}
+
+	conf := endpoints.ServerConfig{ReusePort: true, ListenBacklog: 16}
+	ln, err := listen("127.0.0.1:0", conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// A second listener shares the port
+	second, err := listen(ln.Addr().String(), conf)
+	if err != nil {
+		t.Fatal("Expected the port to be shared:", err)
+	}
+	second.Close()
+
+	// Without the option the port is in use
+	if other, err := net.Listen("tcp", ln.Addr().String()); err == nil {
+		other.Close()
+		t.Error("Expected the port to be in use")
+	}
+}
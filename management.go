@@ -0,0 +1,202 @@
+package main
+
+// Runtime management of the enabled modules
+// and the maintenance mode
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/alice-lg/birdwatcher/endpoints"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// LiveRouter serves requests with the current router. The
+// router is rebuilt and swapped when the enabled modules
+// change, without interrupting requests in flight.
+type LiveRouter struct {
+	sync.Mutex
+	config endpoints.ServerConfig
+	router atomic.Value
+
+	// The modules with a running collector
+	collecting []string
+}
+
+var liveRouter *LiveRouter
+
+// Modules with a background collector. The collectors are
+// started for the modules enabled at startup, so the other
+// ones can not be enabled at runtime.
+var collectorModules = []string{"metrics", "protocols_bgp_history"}
+
+// NewLiveRouter creates a LiveRouter for the server config
+func NewLiveRouter(config endpoints.ServerConfig) *LiveRouter {
+	lr := &LiveRouter{config: config}
+	for _, module := range collectorModules {
+		if endpoints.IsModuleEnabled(module, config.ModulesEnabled) {
+			lr.collecting = append(lr.collecting, module)
+		}
+	}
+	lr.router.Store(makeRouter(config))
+	return lr
+}
+
+// Check if the module can be enabled at runtime
+func (lr *LiveRouter) canEnable(module string) bool {
+	return !endpoints.IsModuleEnabled(module, collectorModules) ||
+		endpoints.IsModuleEnabled(module, lr.collecting)
+}
+
+// ServeHTTP implements the http.Handler interface
+func (lr *LiveRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	lr.router.Load().(*httprouter.Router).ServeHTTP(w, r)
+}
+
+// SetModules replaces the list of enabled modules
+// and rebuilds the router. Modules which can not be
+// enabled at runtime are skipped.
+func (lr *LiveRouter) SetModules(modules []string) {
+	lr.Lock()
+	defer lr.Unlock()
+
+	enabled := []string{}
+	for _, module := range uniqueModules(modules) {
+		if !lr.canEnable(module) {
+			log.Println("Warning: module", module, "can only be enabled at startup")
+			continue
+		}
+		enabled = append(enabled, module)
+	}
+
+	lr.config.ModulesEnabled = enabled
+	lr.router.Store(makeRouter(lr.config))
+}
+
+// SetModuleEnabled enables or disables a single module and
+// returns the new list of enabled modules.
+func (lr *LiveRouter) SetModuleEnabled(module string, enabled bool) []string {
+	lr.Lock()
+	defer lr.Unlock()
+
+	modules := []string{}
+	for _, m := range lr.config.ModulesEnabled {
+		if m != module {
+			modules = append(modules, m)
+		}
+	}
+	if enabled {
+		modules = append(modules, module)
+	}
+
+	lr.config.ModulesEnabled = modules
+	lr.router.Store(makeRouter(lr.config))
+
+	return modules
+}
+
+func writeManagementResponse(w http.ResponseWriter, status int, res interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+// Enable or disable a module:
+//
+//	POST /config/modules/:module/enable
+//	POST /config/modules/:module/disable
+func setModuleEnabled(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if err := endpoints.CheckAccess(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err := endpoints.CheckAdminAuth(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	module := ps.ByName("module")
+	if !isModuleKnown(module) {
+		writeManagementResponse(w, http.StatusNotFound, map[string]string{
+			"error": fmt.Sprintf("unknown module: %s", module),
+		})
+		return
+	}
+	if module == "management" {
+		writeManagementResponse(w, http.StatusBadRequest, map[string]string{
+			"error": "the management module can not be changed at runtime",
+		})
+		return
+	}
+
+	var enabled bool
+	switch ps.ByName("action") {
+	case "enable":
+		enabled = true
+	case "disable":
+		enabled = false
+	default:
+		writeManagementResponse(w, http.StatusNotFound, map[string]string{
+			"error": "action must be enable or disable",
+		})
+		return
+	}
+	if enabled && !liveRouter.canEnable(module) {
+		writeManagementResponse(w, http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("the module %s can only be enabled at startup", module),
+		})
+		return
+	}
+
+	modules := liveRouter.SetModuleEnabled(module, enabled)
+	log.Println("Module", module, "enabled:", enabled)
+
+	writeManagementResponse(w, http.StatusOK, map[string]interface{}{
+		"module":          module,
+		"enabled":         enabled,
+		"modules_enabled": modules,
+	})
+}
+
+// Enable or disable the maintenance mode, in which the data
+// endpoints answer with 503. An optional message is given
+// with the message query parameter:
+//
+//	POST /config/maintenance/enable?message=...
+//	POST /config/maintenance/disable
+func setMaintenance(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if err := endpoints.CheckAccess(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err := endpoints.CheckAdminAuth(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var enabled bool
+	switch ps.ByName("action") {
+	case "enable":
+		enabled = true
+	case "disable":
+		enabled = false
+	default:
+		writeManagementResponse(w, http.StatusNotFound, map[string]string{
+			"error": "action must be enable or disable",
+		})
+		return
+	}
+
+	message := r.URL.Query().Get("message")
+	endpoints.SetMaintenance(enabled, message)
+	log.Println("Maintenance mode enabled:", enabled)
+
+	writeManagementResponse(w, http.StatusOK, map[string]interface{}{
+		"maintenance": enabled,
+	})
+}
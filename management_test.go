@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/endpoints"
+)
+
+func TestSetModuleEnabledCollector(t *testing.T) {
+	endpoints.Conf.AdminTokens = []string{"secret"}
+	defer func() { endpoints.Conf.AdminTokens = nil }()
+
+	liveRouter = NewLiveRouter(endpoints.ServerConfig{
+		ModulesEnabled: []string{"management", "metrics"},
+	})
+	defer func() { liveRouter = nil }()
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/config/modules/status/enable", http.StatusOK},
+		{"/config/modules/protocols_bgp_history/enable", http.StatusBadRequest},
+		{"/config/modules/metrics/disable", http.StatusOK},
+		{"/config/modules/metrics/enable", http.StatusOK},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", test.path, nil)
+		r.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		liveRouter.ServeHTTP(w, r)
+		if w.Code != test.status {
+			t.Error("Expected", test.status, "for", test.path, "got:", w.Code, w.Body.String())
+		}
+	}
+
+	// Modules with a collector are skipped if not enabled at startup
+	liveRouter.SetModules([]string{"management", "protocols_bgp_history"})
+	if modules := liveRouter.config.ModulesEnabled; len(modules) != 1 || modules[0] != "management" {
+		t.Error("Unexpected modules:", modules)
+	}
+}
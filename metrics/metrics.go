@@ -0,0 +1,240 @@
+package metrics
+
+// Minimal metrics registry with Prometheus text exposition
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Metric is a gauge or counter with optional labels.
+// Values are stored by their rendered label set.
+type Metric struct {
+	sync.Mutex
+	Name string
+	Help string
+	Type string
+
+	values map[string]float64
+	tags   map[string][]string
+}
+
+var registry = struct {
+	sync.Mutex
+	metrics []*Metric
+}{}
+
+func register(name string, help string, metricType string) *Metric {
+	m := &Metric{
+		Name:   name,
+		Help:   help,
+		Type:   metricType,
+		values: map[string]float64{},
+		tags:   map[string][]string{},
+	}
+
+	registry.Lock()
+	registry.metrics = append(registry.metrics, m)
+	registry.Unlock()
+
+	return m
+}
+
+// NewGauge registers a metric which can go up and down
+func NewGauge(name string, help string) *Metric {
+	return register(name, help, "gauge")
+}
+
+// NewCounter registers a metric which only goes up
+func NewCounter(name string, help string) *Metric {
+	return register(name, help, "counter")
+}
+
+// labelGuard limits the values of a label. Values are either
+// allowed by the list or, without a list, up to the maximum
+// number of distinct values. Other values are replaced.
+type labelGuard struct {
+	allowed map[string]bool
+	max     int
+	seen    map[string]bool
+}
+
+// OtherLabelValue replaces label values exceeding the guard
+const OtherLabelValue = "other"
+
+var guards = struct {
+	sync.Mutex
+	labels map[string]*labelGuard
+}{
+	labels: map[string]*labelGuard{},
+}
+
+// GuardLabel limits the cardinality of a label. Only the
+// allowed values are kept, if any, otherwise up to max
+// distinct values. Other values are reported as "other".
+func GuardLabel(name string, allowed []string, max int) {
+	guard := &labelGuard{max: max, seen: map[string]bool{}}
+	if len(allowed) > 0 {
+		guard.allowed = map[string]bool{}
+		for _, value := range allowed {
+			guard.allowed[value] = true
+		}
+	}
+
+	guards.Lock()
+	guards.labels[name] = guard
+	guards.Unlock()
+}
+
+// Replace the values of guarded labels exceeding
+// the guard. Empty values are kept.
+func guardLabels(labels []string) []string {
+	guards.Lock()
+	defer guards.Unlock()
+	if len(guards.labels) == 0 {
+		return labels
+	}
+
+	guarded := make([]string, len(labels))
+	copy(guarded, labels)
+	for i := 0; i+1 < len(guarded); i += 2 {
+		guard, ok := guards.labels[guarded[i]]
+		value := guarded[i+1]
+		if !ok || value == "" {
+			continue
+		}
+		switch {
+		case guard.allowed != nil:
+			if !guard.allowed[value] {
+				guarded[i+1] = OtherLabelValue
+			}
+		case guard.max > 0 && !guard.seen[value]:
+			if len(guard.seen) >= guard.max {
+				guarded[i+1] = OtherLabelValue
+			} else {
+				guard.seen[value] = true
+			}
+		}
+	}
+	return guarded
+}
+
+// Render labels given as name, value pairs like
+// {table="master4"}. Label names are sorted.
+func renderLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := []string{}
+	for i := 0; i+1 < len(labels); i += 2 {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[i+1])
+		pairs = append(pairs, labels[i]+`="`+value+`"`)
+	}
+	sort.Strings(pairs)
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Render labels as tags like table:master4, which
+// are sorted by the label name.
+func renderTags(labels []string) []string {
+	tags := []string{}
+	for i := 0; i+1 < len(labels); i += 2 {
+		tags = append(tags, labels[i]+":"+labels[i+1])
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Get the key of the labels and remember their tags.
+// The metric must be locked.
+func (m *Metric) key(labels []string) string {
+	labels = guardLabels(labels)
+	key := renderLabels(labels)
+	if _, ok := m.tags[key]; !ok {
+		m.tags[key] = renderTags(labels)
+	}
+	return key
+}
+
+// Set the value of the metric for the labels
+func (m *Metric) Set(value float64, labels ...string) {
+	m.Lock()
+	m.values[m.key(labels)] = value
+	m.Unlock()
+}
+
+// Replace all values of the metric with the values by
+// a single label. Values sharing a guarded label value
+// are summed up.
+func (m *Metric) Replace(label string, values map[string]float64) {
+	m.Lock()
+	m.values = map[string]float64{}
+	m.tags = map[string][]string{}
+	for value, v := range values {
+		m.values[m.key([]string{label, value})] += v
+	}
+	m.Unlock()
+}
+
+// Add to the value of the metric for the labels
+func (m *Metric) Add(delta float64, labels ...string) {
+	m.Lock()
+	m.values[m.key(labels)] += delta
+	m.Unlock()
+}
+
+// Inc increments the value of the metric for the labels
+func (m *Metric) Inc(labels ...string) {
+	m.Add(1, labels...)
+}
+
+// Value gets the value of the metric for the labels
+func (m *Metric) Value(labels ...string) float64 {
+	m.Lock()
+	defer m.Unlock()
+	return m.values[renderLabels(labels)]
+}
+
+// Get the registered metrics, sorted by name
+func registered() []*Metric {
+	registry.Lock()
+	metrics := make([]*Metric, len(registry.metrics))
+	copy(metrics, registry.metrics)
+	registry.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name < metrics[j].Name
+	})
+	return metrics
+}
+
+// WritePrometheus writes all registered metrics
+// in the Prometheus text exposition format.
+func WritePrometheus(w io.Writer) {
+	metrics := registered()
+	for _, m := range metrics {
+		m.Lock()
+		if len(m.values) == 0 {
+			m.Unlock()
+			continue
+		}
+
+		fmt.Fprintf(w, "# HELP %s %s\n", m.Name, m.Help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", m.Name, m.Type)
+
+		labels := make([]string, 0, len(m.values))
+		for l := range m.values {
+			labels = append(labels, l)
+		}
+		sort.Strings(labels)
+		for _, l := range labels {
+			fmt.Fprintf(w, "%s%s %g\n", m.Name, l, m.values[l])
+		}
+		m.Unlock()
+	}
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	up := NewGauge("test_up", "Test gauge")
+	up.Set(1)
+
+	routes := NewCounter("test_routes_total", "Test counter")
+	routes.Inc("table", "master4")
+	routes.Add(2, "table", "master4")
+	routes.Inc("table", `t"1`)
+
+	NewGauge("test_unset", "Metrics without values are omitted")
+
+	buf := &bytes.Buffer{}
+	WritePrometheus(buf)
+
+	expected := `# HELP test_routes_total Test counter
+# TYPE test_routes_total counter
+test_routes_total{table="master4"} 3
+test_routes_total{table="t\"1"} 1
+# HELP test_up Test gauge
+# TYPE test_up gauge
+test_up 1
+`
+	if buf.String() != expected {
+		t.Error("Unexpected metrics output:\n", buf.String(), "\nexpected:\n", expected)
+	}
+}
+
+func TestStatsdSink(t *testing.T) {
+	runs := NewCounter("test_statsd_runs_total", "Test counter")
+	up := NewGauge("test_statsd_up", "Test gauge")
+
+	buf := &bytes.Buffer{}
+	sink := newStatsdSink(buf, "bw.")
+
+	runs.Add(3, "table", "master4")
+	up.Set(1)
+	if err := sink.Push(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "bw.test_statsd_runs_total:3|c|#table:master4") {
+		t.Error("Expected the counter, got:", buf.String())
+	}
+	if !strings.Contains(buf.String(), "bw.test_statsd_up:1|g") {
+		t.Error("Expected the gauge, got:", buf.String())
+	}
+
+	// Counters are sent as increments
+	buf.Reset()
+	runs.Add(2, "table", "master4")
+	sink.Push()
+	if !strings.Contains(buf.String(), "bw.test_statsd_runs_total:2|c|#table:master4") {
+		t.Error("Expected the increment of the counter, got:", buf.String())
+	}
+
+	buf.Reset()
+	sink.Push()
+	if strings.Contains(buf.String(), "test_statsd_runs_total") {
+		t.Error("Expected unchanged counter to be skipped, got:", buf.String())
+	}
+}
+
+func TestGuardLabel(t *testing.T) {
+	GuardLabel("test_table", []string{"master4"}, 0)
+	GuardLabel("test_protocol", nil, 2)
+	defer func() {
+		guards.Lock()
+		delete(guards.labels, "test_table")
+		delete(guards.labels, "test_protocol")
+		guards.Unlock()
+	}()
+
+	requests := NewCounter("test_guarded_total", "Test counter")
+	requests.Inc("test_table", "master4")
+	requests.Inc("test_table", "t_0097_as3856")
+	requests.Inc("test_table", "t_0175_as15169")
+	requests.Inc("test_table", "")
+	if v := requests.Value("test_table", "other"); v != 2 {
+		t.Error("Expected 2 requests of other tables, got:", v)
+	}
+	if v := requests.Value("test_table", "master4"); v != 1 {
+		t.Error("Expected 1 request of master4, got:", v)
+	}
+	if v := requests.Value("test_table", ""); v != 1 {
+		t.Error("Expected empty label to be kept, got:", v)
+	}
+
+	for _, p := range []string{"R1", "R2", "R3", "R1", "R4"} {
+		requests.Inc("test_protocol", p)
+	}
+	if v := requests.Value("test_protocol", "R1"); v != 2 {
+		t.Error("Expected 2 requests of R1, got:", v)
+	}
+	if v := requests.Value("test_protocol", "other"); v != 2 {
+		t.Error("Expected 2 requests of other protocols, got:", v)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	GuardLabel("test_table", []string{"master4", "master6"}, 0)
+	defer func() {
+		guards.Lock()
+		delete(guards.labels, "test_table")
+		guards.Unlock()
+	}()
+
+	routes := NewGauge("test_replaced_routes", "Test gauge")
+	routes.Set(5, "test_table", "t_0097_as3856")
+	routes.Replace("test_table", map[string]float64{
+		"master4":        10,
+		"t_0097_as3856":  2,
+		"t_0175_as15169": 3,
+	})
+	if v := routes.Value("test_table", "master4"); v != 10 {
+		t.Error("Expected 10 routes in master4, got:", v)
+	}
+	if v := routes.Value("test_table", "other"); v != 5 {
+		t.Error("Expected 5 routes in other tables, got:", v)
+	}
+
+	routes.Replace("test_table", map[string]float64{"master6": 1})
+	if v := routes.Value("test_table", "master4"); v != 0 {
+		t.Error("Expected removed table to be dropped, got:", v)
+	}
+	buf := &bytes.Buffer{}
+	WritePrometheus(buf)
+	if strings.Contains(buf.String(), `test_replaced_routes{test_table="other"}`) {
+		t.Error("Expected replaced values to be dropped, got:", buf.String())
+	}
+}
@@ -0,0 +1,114 @@
+package metrics
+
+// Push of the metrics to StatsD
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Maximum size of a UDP packet sent to StatsD,
+// which fits into the common MTU.
+const statsdPacketSize = 1432
+
+// StatsdSink pushes the registered metrics to StatsD. Labels
+// are sent as tags in the DogStatsD format. Counters are sent
+// as the increment since the last push.
+type StatsdSink struct {
+	Prefix string
+
+	w    io.Writer
+	sent map[string]float64
+}
+
+// NewStatsdSink creates a sink writing to StatsD
+// at the UDP address.
+func NewStatsdSink(address string, prefix string) (*StatsdSink, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	return newStatsdSink(conn, prefix), nil
+}
+
+func newStatsdSink(w io.Writer, prefix string) *StatsdSink {
+	return &StatsdSink{
+		Prefix: prefix,
+		w:      w,
+		sent:   map[string]float64{},
+	}
+}
+
+// Render the lines of the metrics, which changed
+// since the last push.
+func (s *StatsdSink) lines() []string {
+	lines := []string{}
+	for _, m := range registered() {
+		m.Lock()
+		keys := make([]string, 0, len(m.values))
+		for key := range m.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value := m.values[key]
+			statsdType := "g"
+			if m.Type == "counter" {
+				id := m.Name + key
+				value, s.sent[id] = value-s.sent[id], value
+				if value == 0 {
+					continue
+				}
+				statsdType = "c"
+			}
+
+			line := fmt.Sprintf("%s%s:%g|%s", s.Prefix, m.Name, value, statsdType)
+			if tags := m.tags[key]; len(tags) > 0 {
+				line += "|#" + strings.Join(tags, ",")
+			}
+			lines = append(lines, line)
+		}
+		m.Unlock()
+	}
+	return lines
+}
+
+// Push the metrics. The lines are sent in as
+// few packets as possible.
+func (s *StatsdSink) Push() error {
+	packet := &bytes.Buffer{}
+	for _, line := range s.lines() {
+		if packet.Len() > 0 && packet.Len()+len(line)+1 > statsdPacketSize {
+			if _, err := s.w.Write(packet.Bytes()); err != nil {
+				return err
+			}
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	if packet.Len() == 0 {
+		return nil
+	}
+	_, err := s.w.Write(packet.Bytes())
+	return err
+}
+
+// Run pushes the metrics periodically
+func (s *StatsdSink) Run(interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		if err := s.Push(); err != nil {
+			log.Println("Could not push metrics to StatsD:", err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+// Enabled modules from a separate file, which is
+// reloaded on SIGHUP
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/BurntSushi/toml"
+)
+
+// The modules file contains only the list of
+// enabled modules, like the server config:
+//
+//	modules_enabled = ["status", "protocols"]
+type modulesFile struct {
+	ModulesEnabled []string `toml:"modules_enabled"`
+}
+
+// LoadModulesFile reads the enabled modules from the file.
+// Unknown modules are reported, duplicates removed.
+func LoadModulesFile(filename string) ([]string, error) {
+	file := modulesFile{}
+	if _, err := toml.DecodeFile(filename, &file); err != nil {
+		return nil, err
+	}
+
+	for _, module := range file.ModulesEnabled {
+		if !isModuleKnown(module) {
+			log.Println("Warning: unknown module", module, "in", filename)
+		}
+	}
+	return uniqueModules(file.ModulesEnabled), nil
+}
+
+// ReloadModulesFile reloads the enabled modules from the
+// file on SIGHUP and rebuilds the router. The modules are
+// kept if the file can not be loaded.
+func ReloadModulesFile(filename string, lr *LiveRouter) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		modules, err := LoadModulesFile(filename)
+		if err != nil {
+			log.Println("Could not reload modules file:", err)
+			continue
+		}
+		lr.SetModules(modules)
+		log.Println("Reloaded modules from", filename, "enabled:", modules)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestLoadModulesFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "modules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(`modules_enabled = ["status", "protocols", "status"]` + "\n")
+	f.Close()
+
+	modules, err := LoadModulesFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 2 || modules[0] != "status" || modules[1] != "protocols" {
+		t.Error("Unexpected modules:", modules)
+	}
+
+	if _, err := LoadModulesFile(f.Name() + ".missing"); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/alice-lg/birdwatcher/metrics"
+)
+
+type MetricsConfig struct {
+	ProbeInterval int `toml:"probe_interval"`
+	ProbeFailures int `toml:"probe_failures"`
+
+	Sink           string `toml:"sink"`
+	StatsdAddress  string `toml:"statsd_address"`
+	StatsdPrefix   string `toml:"statsd_prefix"`
+	StatsdInterval int    `toml:"statsd_interval"`
+
+	LabelTables    []string `toml:"label_tables"`
+	LabelProtocols []string `toml:"label_protocols"`
+	MaxLabelValues int      `toml:"max_label_values"`
+
+	TableRoutesInterval int `toml:"table_routes_interval"`
+}
+
+var birdUp = metrics.NewGauge(
+	"birdwatcher_bird_up",
+	"Whether BIRD is reachable through birdc (1) or not (0)")
+
+// Periodically probe BIRD with `show status`. BIRD is
+// considered down after a number of consecutive failures.
+func ProbeBird(config MetricsConfig) {
+	interval := time.Duration(config.ProbeInterval) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	maxFailures := config.ProbeFailures
+	if maxFailures <= 0 {
+		maxFailures = 3
+	}
+
+	failures := 0
+	for {
+		// The result is cached for the status endpoint
+		status, _ := bird.Status(bird.RunOptions{Exempt: true})
+		switch {
+		case status == nil: // the command is already running
+		case bird.IsSpecial(status):
+			failures++
+		default:
+			failures = 0
+		}
+
+		if failures == 0 {
+			birdUp.Set(1)
+		} else if failures >= maxFailures {
+			if birdUp.Value() != 0 {
+				log.Println("BIRD is not reachable after", failures, "probes")
+			}
+			birdUp.Set(0)
+		}
+
+		time.Sleep(interval)
+	}
+}
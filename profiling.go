@@ -10,42 +10,93 @@ import (
 )
 
 // Write a heap profile to the given file.
-func createHeapProfile(filename string) {
+func createHeapProfile(filename string) error {
 	f, err := os.Create(filename)
 	if err != nil {
-		log.Fatal("could not create memory profile: ", err)
+		return fmt.Errorf("could not create memory profile: %s", err)
 	}
-	defer f.Close() // error handling omitted for example
+	defer f.Close()
 	if err := pprof.WriteHeapProfile(f); err != nil {
-		log.Fatal("could not write memory profile: ", err)
+		return fmt.Errorf("could not write memory profile: %s", err)
 	}
+	return nil
 }
 
 // Write a memory allocation profile to the given file.
-func createAllocProfile(filename string) {
+func createAllocProfile(filename string) error {
 	f, err := os.Create(filename)
 	if err != nil {
-		log.Fatal("could not create alloc profile: ", err)
+		return fmt.Errorf("could not create alloc profile: %s", err)
 	}
-	defer f.Close() // error handling omitted for example
+	defer f.Close()
 	if err := pprof.Lookup("allocs").WriteTo(f, 0); err != nil {
-		log.Fatal("could not write alloc profile: ", err)
+		return fmt.Errorf("could not write alloc profile: %s", err)
+	}
+	return nil
+}
+
+// Write the heap and allocs profiles of an iteration.
+func writeMemoryProfile(prefix string, t int) error {
+	filename := fmt.Sprintf("%s-heap-%03d", prefix, t)
+	runtime.GC() // get up-to-date statistics (according to docs)
+	if err := createHeapProfile(filename); err != nil {
+		return err
+	}
+	log.Println("Wrote memory heap profile:", filename)
+	filename = fmt.Sprintf("%s-allocs-%03d", prefix, t)
+	if err := createAllocProfile(filename); err != nil {
+		return err
+	}
+	log.Println("Wrote memory allocs profile:", filename)
+	return nil
+}
+
+// Remove the heap and allocs profiles of a previous iteration.
+func removeMemoryProfile(prefix string, t int) {
+	for _, kind := range []string{"heap", "allocs"} {
+		filename := fmt.Sprintf("%s-%s-%03d", prefix, kind, t)
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			log.Println("could not remove memory profile:", err)
+		}
 	}
 }
 
 // Start a goroutine to periodically write memory profiles.
-func startMemoryProfile(prefix string) {
+// If retain is greater than zero, only the last retain
+// profiles are kept on disk. Failed writes are logged and
+// retried after the interval.
+func startMemoryProfile(prefix string, retain int, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
 	t := 0
-	log.Println("Starting memory profiling:", prefix)
+	log.Println("Starting memory profiling:", prefix, "every", interval)
 	for {
-		filename := fmt.Sprintf("%s-heap-%03d", prefix, t)
-		runtime.GC() // get up-to-date statistics (according to docs)
-		createHeapProfile(filename)
-		log.Println("Wrote memory heap profile:", filename)
-		filename = fmt.Sprintf("%s-allocs-%03d", prefix, t)
-		log.Println("Wrote memory allocs profile:", filename)
-		createAllocProfile(filename)
-		time.Sleep(30 * time.Second)
-		t++
+		if err := writeMemoryProfile(prefix, t); err != nil {
+			log.Println(err)
+		} else {
+			if retain > 0 && t >= retain {
+				removeMemoryProfile(prefix, t-retain)
+			}
+			t++
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Write a CPU profile to the given file. Profiling is
+// stopped after the given duration.
+func startCPUProfile(filename string, duration time.Duration) {
+	f, err := os.Create(filename)
+	if err != nil {
+		log.Fatal("could not create CPU profile: ", err)
+	}
+	defer f.Close()
+	if err := pprof.StartCPUProfile(f); err != nil {
+		log.Fatal("could not start CPU profile: ", err)
 	}
+	log.Println("Starting CPU profiling:", filename, "for", duration)
+	time.Sleep(duration)
+	pprof.StopCPUProfile()
+	log.Println("Wrote CPU profile:", filename)
 }
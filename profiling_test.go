@@ -0,0 +1,36 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteMemoryProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "birdwatcher-profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	prefix := filepath.Join(dir, "mem")
+	if err := writeMemoryProfile(prefix, 1); err != nil {
+		t.Error("Expected the profile to be written, got:", err)
+	}
+	for _, filename := range []string{prefix + "-heap-001", prefix + "-allocs-001"} {
+		if _, err := os.Stat(filename); err != nil {
+			t.Error("Expected profile", filename, "got:", err)
+		}
+	}
+
+	removeMemoryProfile(prefix, 1)
+	if _, err := os.Stat(prefix + "-heap-001"); !os.IsNotExist(err) {
+		t.Error("Expected the profile to be removed, got:", err)
+	}
+
+	// A missing directory must not be fatal
+	if err := writeMemoryProfile(filepath.Join(dir, "missing", "mem"), 0); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
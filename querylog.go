@@ -0,0 +1,154 @@
+package main
+
+// Query log of the requests served
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/endpoints"
+)
+
+type LoggingConfig struct {
+	// Fraction (0.0 - 1.0) of successful requests written to
+	// the query log. Errors and slow queries are always logged.
+	SampleRate *float64 `toml:"sample_rate"`
+	// Requests taking longer (in milliseconds) are slow queries
+	SlowQuery int `toml:"slow_query"`
+	// Size of the responses logged, either the bytes written
+	// to the client (written) or the bytes of the serialized
+	// response before compression (serialized)
+	ResponseSize string `toml:"response_size"`
+}
+
+// Get the sample rate from the config, all
+// requests are logged if not configured.
+func (c LoggingConfig) sampleRate() float64 {
+	switch {
+	case c.SampleRate == nil || *c.SampleRate > 1:
+		return 1
+	case *c.SampleRate < 0:
+		return 0
+	}
+	return *c.SampleRate
+}
+
+// Get the duration after which a request is a slow query
+func (c LoggingConfig) slowQuery() time.Duration {
+	if c.SlowQuery > 0 {
+		return time.Duration(c.SlowQuery) * time.Millisecond
+	}
+	return time.Second
+}
+
+// Check if the serialized size of the responses is
+// logged instead of the bytes written.
+func (c LoggingConfig) serializedSize() bool {
+	return c.ResponseSize == "serialized"
+}
+
+// Decide if a request is written to the query log.
+// Successful requests are sampled, errors and slow
+// queries are always logged.
+func (c LoggingConfig) sampled(status int, duration time.Duration) bool {
+	if status >= http.StatusBadRequest || duration >= c.slowQuery() {
+		return true
+	}
+	rate := c.sampleRate()
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// Record the status and size of the response. The sizes
+// of endpoint responses are recorded by the endpoint.
+type loggedResponse struct {
+	http.ResponseWriter
+	status   int
+	size     int
+	recorded *endpoints.ResponseSize
+}
+
+// RecordResponseSize implements the
+// endpoints.ResponseSizeRecorder interface.
+func (w *loggedResponse) RecordResponseSize(size endpoints.ResponseSize) {
+	w.recorded = &size
+}
+
+// Get the size of the response to log
+func (w *loggedResponse) loggedSize(serialized bool) int {
+	switch {
+	case w.recorded == nil:
+		return w.size
+	case serialized:
+		return w.recorded.Serialized
+	}
+	return w.recorded.Written
+}
+
+func (w *loggedResponse) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *loggedResponse) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// Hijack the connection, which is required to
+// upgrade to a WebSocket connection.
+func (w *loggedResponse) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response does not support hijacking")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// Write the requests handled to the query log in the
+// common log format with the duration and the request
+// ID set by the endpoint appended.
+func queryLogHandler(logger *log.Logger, config LoggingConfig, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		res := &loggedResponse{ResponseWriter: w}
+		handler.ServeHTTP(res, r)
+
+		if res.status == 0 {
+			res.status = http.StatusOK
+		}
+		duration := time.Since(start)
+		if !config.sampled(res.status, duration) {
+			return
+		}
+
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		id := res.Header().Get(endpoints.RequestIDHeader)
+		if id == "" {
+			id = "-"
+		}
+		logger.Printf("%s - - [%s] \"%s %s %s\" %d %d %.3f %s\n",
+			host,
+			start.Format("02/Jan/2006:15:04:05 -0700"),
+			r.Method, r.RequestURI, r.Proto,
+			res.status, res.loggedSize(config.serializedSize()),
+			duration.Seconds(),
+			id)
+	})
+}
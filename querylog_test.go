@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/endpoints"
+)
+
+func TestLoggingSampled(t *testing.T) {
+	never := 0.0
+	conf := LoggingConfig{SampleRate: &never, SlowQuery: 500}
+
+	tests := []struct {
+		status   int
+		duration time.Duration
+		sampled  bool
+	}{
+		{http.StatusOK, 10 * time.Millisecond, false},
+		{http.StatusOK, 600 * time.Millisecond, true},
+		{http.StatusNotFound, 10 * time.Millisecond, true},
+		{http.StatusInternalServerError, 10 * time.Millisecond, true},
+	}
+	for _, test := range tests {
+		if conf.sampled(test.status, test.duration) != test.sampled {
+			t.Error("Expected", test.status, "after", test.duration, "sampled to be", test.sampled)
+		}
+	}
+
+	// All requests are logged by default
+	if !(LoggingConfig{}).sampled(http.StatusOK, 0) {
+		t.Error("Expected requests to be logged without sample rate")
+	}
+}
+
+func TestQueryLogHandler(t *testing.T) {
+	never := 0.0
+	buf := &bytes.Buffer{}
+	logger := log.New(buf, "", 0)
+
+	handler := queryLogHandler(logger, LoggingConfig{SampleRate: &never},
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/missing" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte("{}"))
+		}))
+
+	for _, path := range []string{"/status", "/missing"} {
+		r := httptest.NewRequest("GET", path, nil)
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	handler = queryLogHandler(logger, LoggingConfig{},
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(endpoints.RequestIDHeader, "abc-123")
+			w.Write([]byte("{}"))
+		}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/status", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatal("Expected the failed request and the request with the default sample rate to be logged, got:", lines)
+	}
+	if !strings.Contains(lines[0], `"GET /missing HTTP/1.1" 404 19`) ||
+		!strings.HasSuffix(lines[0], " -") {
+		t.Error("Unexpected query log line:", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], " abc-123") {
+		t.Error("Expected the request ID in the query log line:", lines[1])
+	}
+}
+
+func TestQueryLogResponseSize(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := log.New(buf, "", 0)
+	endpoint := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+		w.(endpoints.ResponseSizeRecorder).RecordResponseSize(
+			endpoints.ResponseSize{Serialized: 100, Written: 2})
+	})
+
+	for _, mode := range []string{"", "serialized"} {
+		handler := queryLogHandler(logger, LoggingConfig{ResponseSize: mode}, endpoint)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/status", nil))
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatal("Expected 2 lines, got:", lines)
+	}
+	if !strings.Contains(lines[0], `HTTP/1.1" 200 2 `) {
+		t.Error("Expected the bytes written to be logged:", lines[0])
+	}
+	if !strings.Contains(lines[1], `HTTP/1.1" 200 100 `) {
+		t.Error("Expected the serialized size to be logged:", lines[1])
+	}
+}
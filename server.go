@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/alice-lg/birdwatcher/endpoints"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Time to wait for requests in flight on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// Get the listeners from the bird config. If no listeners
+// are configured, the listen address is used with TLS as
+// configured for the server.
+func getListeners(birdConf bird.BirdConfig, serverConf endpoints.ServerConfig) []bird.ListenerConfig {
+	if len(birdConf.Listeners) > 0 {
+		return birdConf.Listeners
+	}
+
+	return []bird.ListenerConfig{{
+		Address: birdConf.Listen,
+		TLS:     serverConf.EnableTLS,
+	}}
+}
+
+// Get the handler for a listener. HTTP/2 is negotiated
+// by TLS listeners, plaintext listeners accept HTTP/2
+// without TLS (h2c) only if enabled.
+func listenerHandler(
+	handler http.Handler,
+	serverConf endpoints.ServerConfig,
+	useTLS bool,
+) http.Handler {
+	if useTLS || !serverConf.EnableH2C {
+		return handler
+	}
+	return h2c.NewHandler(handler, &http2.Server{})
+}
+
+// Start a http.Server for each listener, all sharing the
+// same handler. Serve blocks until all servers are shut
+// down, which happens on SIGINT or SIGTERM. The stops are
+// called after the HTTP servers are shut down.
+func Serve(
+	listeners []bird.ListenerConfig,
+	serverConf endpoints.ServerConfig,
+	handler http.Handler,
+	stops ...func(),
+) {
+	servers := []*http.Server{}
+	wg := &sync.WaitGroup{}
+
+	// The connection limit applies to all listeners
+	limiter := serverConnLimiter(serverConf)
+	if limiter != nil {
+		handler = limiter.Handler(handler)
+	}
+
+	for _, listener := range listeners {
+		ln, err := listen(listener.Address, serverConf)
+		if err != nil {
+			log.Fatal("Could not listen on ", listener.Address, ": ", err)
+		}
+
+		srv := &http.Server{
+			Handler: listenerHandler(handler, serverConf, listener.TLS),
+		}
+		if limiter != nil {
+			srv.ConnState = limiter.ConnState
+		}
+		servers = append(servers, srv)
+
+		wg.Add(1)
+		go func(srv *http.Server, ln net.Listener, useTLS bool) {
+			defer wg.Done()
+			var err error
+			if useTLS {
+				err = srv.ServeTLS(ln, serverConf.Crt, serverConf.Key)
+			} else {
+				err = srv.Serve(ln)
+			}
+			if err != http.ErrServerClosed {
+				log.Fatal(err)
+			}
+		}(srv, ln, listener.TLS)
+	}
+
+	// Drain all servers on shutdown
+	wg.Add(len(stops))
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		s := <-sig
+		log.Println("Received", s, "shutting down")
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		for _, srv := range servers {
+			if err := srv.Shutdown(ctx); err != nil {
+				log.Println("Error during shutdown:", err)
+			}
+		}
+		for _, stop := range stops {
+			stop()
+			wg.Done()
+		}
+	}()
+
+	wg.Wait()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alice-lg/birdwatcher/endpoints"
+	"golang.org/x/net/http2"
+)
+
+func TestListenerHandlerH2C(t *testing.T) {
+	// Large responses are written in many frames
+	body := bytes.Repeat([]byte("birdwatcher"), 1<<20)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	})
+
+	conf := endpoints.ServerConfig{EnableH2C: true}
+	srv := httptest.NewServer(listenerHandler(handler, conf, false))
+	defer srv.Close()
+
+	client := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+	res, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if res.ProtoMajor != 2 {
+		t.Error("Expected HTTP/2, got:", res.Proto)
+	}
+	received, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(received, body) {
+		t.Error("Expected the complete response, got", len(received), "bytes")
+	}
+
+	// Without h2c, plaintext listeners only accept HTTP/1
+	plain := httptest.NewServer(listenerHandler(handler, endpoints.ServerConfig{}, false))
+	defer plain.Close()
+	if _, err := client.Get(plain.URL); err == nil {
+		t.Error("Expected HTTP/2 without TLS to fail")
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+// Selection of the sink the metrics are exposed with
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/metrics"
+)
+
+// The sink of the metrics, the /metrics endpoint
+// is only served for the prometheus sink.
+var metricsSink = "prometheus"
+
+// Get the configured sink, defaults to prometheus
+func (c MetricsConfig) sink() string {
+	if c.Sink == "" {
+		return "prometheus"
+	}
+	return c.Sink
+}
+
+// Get the interval to push the metrics to StatsD
+// from the config, defaults to 10 seconds.
+func (c MetricsConfig) statsdInterval() time.Duration {
+	if c.StatsdInterval > 0 {
+		return time.Duration(c.StatsdInterval) * time.Second
+	}
+	return 10 * time.Second
+}
+
+// Get the maximum number of distinct values of the
+// table and protocol labels, defaults to 100.
+func (c MetricsConfig) maxLabelValues() int {
+	if c.MaxLabelValues > 0 {
+		return c.MaxLabelValues
+	}
+	return 100
+}
+
+// GuardMetricsLabels limits the table and protocol labels
+// to the configured values or the maximum number of values.
+func GuardMetricsLabels(config MetricsConfig) {
+	metrics.GuardLabel("table", config.LabelTables, config.maxLabelValues())
+	metrics.GuardLabel("protocol", config.LabelProtocols, config.maxLabelValues())
+}
+
+// StartMetricsSink selects the sink of the metrics.
+// Metrics are pushed periodically to StatsD.
+func StartMetricsSink(config MetricsConfig) error {
+	switch config.sink() {
+	case "prometheus", "none":
+	case "statsd":
+		sink, err := metrics.NewStatsdSink(config.StatsdAddress, config.StatsdPrefix)
+		if err != nil {
+			return err
+		}
+		log.Println("Pushing metrics to StatsD at", config.StatsdAddress)
+		go sink.Run(config.statsdInterval())
+	default:
+		return fmt.Errorf("unknown metrics sink: %s", config.Sink)
+	}
+
+	metricsSink = config.sink()
+	return nil
+}
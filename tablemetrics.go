@@ -0,0 +1,62 @@
+package main
+
+// Number of routes per table, counted periodically
+
+import (
+	"log"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+	"github.com/alice-lg/birdwatcher/metrics"
+)
+
+var tableRoutes = metrics.NewGauge(
+	"birdwatcher_table_routes",
+	"Number of routes per routing table")
+
+// Get the route counts of the tables, decoded
+// from the redis cache as float64.
+func tableRoutesCounts(res bird.Parsed) map[string]float64 {
+	counts := map[string]float64{}
+	tables, _ := res["tables"].(bird.Parsed)
+	if tables == nil {
+		if m, ok := res["tables"].(map[string]interface{}); ok {
+			tables = bird.Parsed(m)
+		}
+	}
+	for table, routes := range tables {
+		switch n := routes.(type) {
+		case int64:
+			counts[table] = float64(n)
+		case float64:
+			counts[table] = n
+		}
+	}
+	return counts
+}
+
+// Periodically count the routes of all tables, if an
+// interval is configured. The counts are cached like the
+// count endpoints and rate limited like requests. The
+// previous values are kept if BIRD is not reachable.
+func CountTableRoutes(config MetricsConfig) {
+	if config.TableRoutesInterval <= 0 {
+		return
+	}
+	interval := time.Duration(config.TableRoutesInterval) * time.Second
+	for {
+		res, _ := bird.RoutesTablesCount(bird.RunOptions{
+			UseCache: true,
+			Module:   "routes_count_table",
+		})
+		switch {
+		case res == nil: // a count is already running or rate limited
+		case bird.IsSpecial(res):
+			log.Println("Could not count the routes of the tables:", res["error"])
+		default:
+			tableRoutes.Replace("table", tableRoutesCounts(res))
+		}
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alice-lg/birdwatcher/bird"
+)
+
+func TestTableRoutesCounts(t *testing.T) {
+	counts := tableRoutesCounts(bird.Parsed{
+		"tables": bird.Parsed{"master4": int64(10), "master6": int64(3)},
+	})
+	if counts["master4"] != 10 || counts["master6"] != 3 {
+		t.Error("Expected counts of master4 and master6, got:", counts)
+	}
+
+	// Decoded from the redis cache
+	counts = tableRoutesCounts(bird.Parsed{
+		"tables": map[string]interface{}{"master4": float64(7)},
+	})
+	if len(counts) != 1 || counts["master4"] != 7 {
+		t.Error("Expected decoded count of master4, got:", counts)
+	}
+}
+
+func TestCountTableRoutesDisabled(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		CountTableRoutes(MetricsConfig{})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the routes not to be counted without an interval")
+	}
+}